@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// TokenKey is the metadata key clients send their token in.
+const TokenKey = "x-client-token"
+
+// Sign returns a token proving id was handed out for roomKey by a holder of
+// secret, normally the Auth service.
+func Sign(secret, id, roomKey string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(id + "\x00" + roomKey))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func Verify(secret, id, roomKey, token string) bool {
+	return hmac.Equal([]byte(Sign(secret, id, roomKey)), []byte(token))
+}
+
+// FromContext returns the token in the incoming metadata, or "" if there is none.
+func FromContext(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(TokenKey); len(v) > 0 {
+		return v[0]
+	}
+
+	return ""
+}
+
+// WithToken attaches token to the outgoing metadata of ctx.
+func WithToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, TokenKey, token)
+}
@@ -0,0 +1,93 @@
+// Package backplane carries events between the replicas of a service, so that
+// players connected to different replicas can share a room. Events are
+// published to a subject and received by every replica subscribed to it, the
+// publisher included.
+package backplane
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+// Backplane is a publish/subscribe bus between replicas. Delivery is at most
+// once: events published while a replica is cut off are lost to it.
+type Backplane interface {
+	// Publish sends data to every subscriber of subject.
+	Publish(subject string, data []byte) error
+	// Subscribe calls handle with every event published to subject from now
+	// on, in the order they were published. handle must not block as the
+	// events after it wait for it.
+	Subscribe(subject string, handle func(data []byte)) error
+	// Close stops publishing and delivering events.
+	Close() error
+}
+
+// Open returns the backplane at addr, either empty for one only reaching this
+// process or redis://[:password@]host:port for a Redis server or a Broker.
+func Open(addr string) (Backplane, error) {
+	if addr == "" {
+		return NewLocal(), nil
+	}
+
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "redis" || u.Host == "" {
+		return nil, fmt.Errorf("backplane %q is not a redis://host:port address", addr)
+	}
+	password, _ := u.User.Password()
+
+	return NewRedis(u.Host, password), nil
+}
+
+// Local is a backplane within one process. Events are handled on the
+// publisher's goroutine before Publish returns.
+type Local struct {
+	lock     sync.RWMutex
+	handlers map[string][]func([]byte)
+}
+
+func NewLocal() *Local {
+	return &Local{handlers: make(map[string][]func([]byte))}
+}
+
+func (l *Local) Publish(subject string, data []byte) error {
+	l.lock.RLock()
+	closed := l.handlers == nil
+	handlers := l.handlers[subject]
+	l.lock.RUnlock()
+
+	if closed {
+		return ErrClosed
+	}
+
+	// Handlers may publish in turn, so they are called without the lock.
+	for _, handle := range handlers {
+		handle(data)
+	}
+
+	return nil
+}
+
+func (l *Local) Subscribe(subject string, handle func([]byte)) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.handlers == nil {
+		return ErrClosed
+	}
+	// Copied so a Publish in progress keeps the handlers it started with.
+	handlers := append([]func([]byte){}, l.handlers[subject]...)
+	l.handlers[subject] = append(handlers, handle)
+	return nil
+}
+
+func (l *Local) Close() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.handlers = nil
+	return nil
+}
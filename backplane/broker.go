@@ -0,0 +1,267 @@
+package backplane
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/richardjaytea/infipic/fanout"
+)
+
+// brokerQueueSize is how many replies and messages a connection to the broker
+// can fall behind by before it is disconnected.
+const brokerQueueSize = 1024
+
+// brokerQueues reports the depth of every connection's queue in /debug/vars.
+var brokerQueues = fanout.NewGroup("backplane_broker_queues")
+
+// Broker is a stand-in for Redis pub/sub, so replicas can share rooms without
+// a Redis server. It answers SUBSCRIBE, UNSUBSCRIBE, PUBLISH, PING and QUIT,
+// and accepts any AUTH.
+type Broker struct {
+	lock      sync.Mutex
+	subs      map[string]map[*brokerConn]struct{}
+	conns     map[*brokerConn]struct{}
+	listeners map[net.Listener]struct{}
+	closed    bool
+}
+
+// brokerConn is a client of the broker. Everything it is sent goes through its
+// queue, so a slow client only holds up itself.
+type brokerConn struct {
+	conn net.Conn
+	q    *fanout.Queue
+	// subjects is guarded by the broker's lock.
+	subjects map[string]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subs:      make(map[string]map[*brokerConn]struct{}),
+		conns:     make(map[*brokerConn]struct{}),
+		listeners: make(map[net.Listener]struct{}),
+	}
+}
+
+// Serve accepts clients on lis until the broker is closed, when it returns
+// ErrClosed.
+func (b *Broker) Serve(lis net.Listener) error {
+	b.lock.Lock()
+	if b.closed {
+		b.lock.Unlock()
+		return ErrClosed
+	}
+	b.listeners[lis] = struct{}{}
+	b.lock.Unlock()
+
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			b.lock.Lock()
+			closed := b.closed
+			b.lock.Unlock()
+
+			if closed {
+				return ErrClosed
+			}
+			return err
+		}
+
+		go b.serveConn(conn)
+	}
+}
+
+// Close stops every listener and disconnects every client.
+func (b *Broker) Close() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.closed = true
+	for lis := range b.listeners {
+		lis.Close()
+	}
+	for c := range b.conns {
+		c.conn.Close()
+	}
+
+	return nil
+}
+
+func (b *Broker) serveConn(conn net.Conn) {
+	c := &brokerConn{conn: conn, subjects: make(map[string]struct{})}
+	c.q = brokerQueues.NewQueue(brokerQueueSize, fanout.Disconnect, func() {
+		conn.Close()
+	})
+
+	b.lock.Lock()
+	if b.closed {
+		b.lock.Unlock()
+		conn.Close()
+		c.q.Close()
+		return
+	}
+	b.conns[c] = struct{}{}
+	b.lock.Unlock()
+
+	defer func() {
+		b.lock.Lock()
+		b.unsubscribe(c, nil)
+		delete(b.conns, c)
+		b.lock.Unlock()
+
+		c.q.Close()
+		conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	for {
+		v, err := readValue(r)
+		if err != nil {
+			return
+		}
+
+		args, ok := commandArgs(v)
+		if !ok {
+			c.write([]byte("-ERR expected a command\r\n"))
+			continue
+		}
+
+		switch strings.ToUpper(args[0]) {
+		case "PING":
+			b.lock.Lock()
+			subscribed := len(c.subjects) > 0
+			b.lock.Unlock()
+
+			if subscribed {
+				c.write(appendBulks(nil, "pong", ""))
+			} else {
+				c.write([]byte("+PONG\r\n"))
+			}
+		case "AUTH":
+			c.write([]byte("+OK\r\n"))
+		case "SUBSCRIBE":
+			b.lock.Lock()
+			reply := b.subscribe(c, args[1:])
+			b.lock.Unlock()
+			c.write(reply)
+		case "UNSUBSCRIBE":
+			b.lock.Lock()
+			reply := b.unsubscribe(c, args[1:])
+			b.lock.Unlock()
+			c.write(reply)
+		case "PUBLISH":
+			if len(args) != 3 {
+				c.write([]byte("-ERR wrong number of arguments for PUBLISH\r\n"))
+				continue
+			}
+			n := b.publish(args[1], args[2])
+			c.write(appendInt(nil, n))
+		case "QUIT":
+			c.write([]byte("+OK\r\n"))
+			return
+		default:
+			c.write([]byte("-ERR unknown command " + strconv.Quote(args[0]) + "\r\n"))
+		}
+	}
+}
+
+// subscribe subscribes c to subjects and returns the confirmations. It must
+// be called with b.lock held.
+func (b *Broker) subscribe(c *brokerConn, subjects []string) []byte {
+	var reply []byte
+	for _, subject := range subjects {
+		if b.subs[subject] == nil {
+			b.subs[subject] = make(map[*brokerConn]struct{})
+		}
+		b.subs[subject][c] = struct{}{}
+		c.subjects[subject] = struct{}{}
+		reply = appendCount(reply, "subscribe", subject, len(c.subjects))
+	}
+
+	return reply
+}
+
+// unsubscribe unsubscribes c from subjects, or from all of them when there
+// are none, and returns the confirmations. It must be called with b.lock held.
+func (b *Broker) unsubscribe(c *brokerConn, subjects []string) []byte {
+	if len(subjects) == 0 {
+		for subject := range c.subjects {
+			subjects = append(subjects, subject)
+		}
+	}
+
+	var reply []byte
+	for _, subject := range subjects {
+		delete(c.subjects, subject)
+		delete(b.subs[subject], c)
+		if len(b.subs[subject]) == 0 {
+			delete(b.subs, subject)
+		}
+		reply = appendCount(reply, "unsubscribe", subject, len(c.subjects))
+	}
+
+	return reply
+}
+
+// publish queues data to every subscriber of subject, returning how many there
+// are.
+func (b *Broker) publish(subject, data string) int {
+	m := appendBulks(nil, "message", subject, data)
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for c := range b.subs[subject] {
+		c.write(m)
+	}
+
+	return len(b.subs[subject])
+}
+
+func (c *brokerConn) write(b []byte) {
+	if len(b) == 0 {
+		return
+	}
+
+	c.q.Push(func() error {
+		c.conn.SetWriteDeadline(time.Now().Add(ioTimeout))
+		_, err := c.conn.Write(b)
+		return err
+	})
+}
+
+// commandArgs returns the command in v, which clients send as an array of bulk
+// strings.
+func commandArgs(v interface{}) ([]string, bool) {
+	values, ok := v.([]interface{})
+	if !ok || len(values) == 0 {
+		return nil, false
+	}
+
+	args := make([]string, len(values))
+	for i, v := range values {
+		if args[i], ok = v.(string); !ok {
+			return nil, false
+		}
+	}
+
+	return args, true
+}
+
+// appendCount appends a (un)subscribe confirmation with the number of subjects
+// the client is left subscribed to.
+func appendCount(b []byte, kind, subject string, n int) []byte {
+	b = append(b, "*3\r\n"...)
+	b = appendBulk(b, kind)
+	b = appendBulk(b, subject)
+	return appendInt(b, n)
+}
+
+func appendInt(b []byte, n int) []byte {
+	b = append(b, ':')
+	b = strconv.AppendInt(b, int64(n), 10)
+	return append(b, '\r', '\n')
+}
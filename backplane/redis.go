@@ -0,0 +1,378 @@
+package backplane
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/richardjaytea/infipic/retry"
+)
+
+// ErrClosed is returned once a backplane or broker has been closed.
+var ErrClosed = errors.New("backplane: closed")
+
+var errProtocol = errors.New("backplane: malformed reply")
+
+const (
+	dialTimeout = 5 * time.Second
+	ioTimeout   = 5 * time.Second
+	// pingInterval is how often the subscriber pings, so a connection that
+	// went away without closing is noticed.
+	pingInterval = 30 * time.Second
+	// maxBulk bounds a single event, which is far smaller.
+	maxBulk = 1 << 20
+)
+
+// replyError is an error reply, such as a wrong password.
+type replyError string
+
+func (e replyError) Error() string {
+	return "backplane: " + string(e)
+}
+
+// appendBulks appends a RESP array of bulk strings, which is how commands are
+// sent and messages delivered.
+func appendBulks(b []byte, items ...string) []byte {
+	b = append(b, '*')
+	b = strconv.AppendInt(b, int64(len(items)), 10)
+	b = append(b, '\r', '\n')
+	for _, v := range items {
+		b = appendBulk(b, v)
+	}
+
+	return b
+}
+
+func appendBulk(b []byte, v string) []byte {
+	b = append(b, '$')
+	b = strconv.AppendInt(b, int64(len(v)), 10)
+	b = append(b, '\r', '\n')
+	b = append(b, v...)
+	return append(b, '\r', '\n')
+}
+
+// readValue reads one RESP value: a string for simple and bulk strings, an
+// int64, a []interface{} for arrays and nil for null. Error replies are
+// returned as a replyError.
+func readValue(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errProtocol
+	}
+
+	kind, rest := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return rest, nil
+	case '-':
+		return nil, replyError(rest)
+	case ':':
+		return strconv.ParseInt(rest, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(rest)
+		if err != nil || n > maxBulk {
+			return nil, errProtocol
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		return string(b[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(rest)
+		if err != nil || n > maxBulk {
+			return nil, errProtocol
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		values := make([]interface{}, n)
+		for i := range values {
+			if values[i], err = readValue(r); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+
+	return nil, fmt.Errorf("backplane: unexpected reply %q", line)
+}
+
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// send writes a command without waiting for its reply.
+func (c *redisConn) send(args ...string) error {
+	c.conn.SetWriteDeadline(time.Now().Add(ioTimeout))
+	_, err := c.conn.Write(appendBulks(nil, args...))
+	return err
+}
+
+// do sends a command and reads its reply. It is only used before subscribing,
+// as subscribed connections are sent messages in between.
+func (c *redisConn) do(args ...string) (interface{}, error) {
+	if err := c.send(args...); err != nil {
+		return nil, err
+	}
+	c.conn.SetReadDeadline(time.Now().Add(ioTimeout))
+	return readValue(c.r)
+}
+
+// Redis is a backplane over Redis pub/sub, which Broker also speaks. It
+// publishes and subscribes on a connection each, reconnecting with backoff
+// when one drops. The subscriber resubscribes to every subject once back.
+type Redis struct {
+	addr     string
+	password string
+
+	// pubLock guards the publishing connection, which is dialed again no
+	// sooner than pubRetry once it fails.
+	pubLock    sync.Mutex
+	pub        *redisConn
+	pubRetry   time.Time
+	pubBackoff retry.Backoff
+
+	// lock guards handlers and the subscribed connection.
+	lock     sync.Mutex
+	handlers map[string][]func([]byte)
+	sub      *redisConn
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// NewRedis returns a backplane connecting to the server at addr, in the
+// background so it can start before the server.
+func NewRedis(addr, password string) *Redis {
+	r := &Redis{
+		addr:     addr,
+		password: password,
+		handlers: make(map[string][]func([]byte)),
+		closed:   make(chan struct{}),
+	}
+	go r.run()
+
+	return r
+}
+
+func (r *Redis) dial() (*redisConn, error) {
+	conn, err := net.DialTimeout("tcp", r.addr, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn)}
+	if r.password != "" {
+		if _, err := c.do("AUTH", r.password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// Publish fails straight away while the server is being backed off from,
+// rather than holding the caller up dialing it.
+func (r *Redis) Publish(subject string, data []byte) error {
+	r.pubLock.Lock()
+	defer r.pubLock.Unlock()
+
+	select {
+	case <-r.closed:
+		return ErrClosed
+	default:
+	}
+
+	if r.pub == nil {
+		if time.Now().Before(r.pubRetry) {
+			return fmt.Errorf("backplane: %s is unavailable", r.addr)
+		}
+		c, err := r.dial()
+		if err != nil {
+			r.pubRetry = time.Now().Add(r.pubBackoff.Next())
+			return err
+		}
+		r.pub = c
+		r.pubBackoff.Reset()
+	}
+
+	if _, err := r.pub.do("PUBLISH", subject, string(data)); err != nil {
+		r.pub.conn.Close()
+		r.pub = nil
+		return err
+	}
+
+	return nil
+}
+
+func (r *Redis) Subscribe(subject string, handle func([]byte)) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	select {
+	case <-r.closed:
+		return ErrClosed
+	default:
+	}
+
+	handlers := append([]func([]byte){}, r.handlers[subject]...)
+	r.handlers[subject] = append(handlers, handle)
+	if len(handlers) == 0 && r.sub != nil {
+		// A failed write ends the connection, which resubscribes to the
+		// subject once it is back.
+		if err := r.sub.send("SUBSCRIBE", subject); err != nil {
+			r.sub.conn.Close()
+		}
+	}
+
+	return nil
+}
+
+func (r *Redis) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.closed)
+	})
+
+	r.lock.Lock()
+	if r.sub != nil {
+		r.sub.conn.Close()
+	}
+	r.lock.Unlock()
+
+	r.pubLock.Lock()
+	if r.pub != nil {
+		r.pub.conn.Close()
+		r.pub = nil
+	}
+	r.pubLock.Unlock()
+
+	return nil
+}
+
+// run keeps the subscribed connection up until the backplane is closed.
+func (r *Redis) run() {
+	var b retry.Backoff
+	for {
+		c, err := r.subscribe()
+		if err == nil {
+			b.Reset()
+			err = r.receive(c)
+		}
+
+		select {
+		case <-r.closed:
+			return
+		default:
+		}
+
+		d := b.Next()
+		log.Printf("Backplane connection to %s lost, reconnecting in %s: %v", r.addr, d.Round(time.Millisecond), err)
+		select {
+		case <-r.closed:
+			return
+		case <-time.After(d):
+		}
+	}
+}
+
+// subscribe dials a connection subscribed to every subject handled so far.
+func (r *Redis) subscribe() (*redisConn, error) {
+	c, err := r.dial()
+	if err != nil {
+		return nil, err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	select {
+	case <-r.closed:
+		c.conn.Close()
+		return nil, ErrClosed
+	default:
+	}
+
+	for subject := range r.handlers {
+		if err := c.send("SUBSCRIBE", subject); err != nil {
+			c.conn.Close()
+			return nil, err
+		}
+	}
+	r.sub = c
+
+	return c, nil
+}
+
+// receive hands the messages on c to their handlers until c fails.
+func (r *Redis) receive(c *redisConn) error {
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		r.lock.Lock()
+		r.sub = nil
+		r.lock.Unlock()
+		c.conn.Close()
+	}()
+	go r.ping(c, done)
+
+	for {
+		c.conn.SetReadDeadline(time.Now().Add(2 * pingInterval))
+		v, err := readValue(c.r)
+		if err != nil {
+			return err
+		}
+
+		m, ok := v.([]interface{})
+		if !ok || len(m) != 3 || m[0] != "message" {
+			continue
+		}
+		subject, _ := m[1].(string)
+		data, _ := m[2].(string)
+
+		r.lock.Lock()
+		handlers := r.handlers[subject]
+		r.lock.Unlock()
+
+		for _, handle := range handlers {
+			handle([]byte(data))
+		}
+	}
+}
+
+// ping pings the subscribed connection c every pingInterval until done. The
+// pongs keep receive's read deadline from passing.
+func (r *Redis) ping(c *redisConn, done <-chan struct{}) {
+	t := time.NewTicker(pingInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
+		}
+
+		r.lock.Lock()
+		err := c.send("PING")
+		r.lock.Unlock()
+		if err != nil {
+			c.conn.Close()
+			return
+		}
+	}
+}
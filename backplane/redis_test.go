@@ -0,0 +1,244 @@
+package backplane
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// waitTimeout bounds how long a test waits for a connection to be dropped,
+// redialed or subscribed, which takes a reconnect backoff at most.
+const waitTimeout = 5 * time.Second
+
+// startBroker serves a broker on a loopback port until the test ends.
+func startBroker(t *testing.T) (*Broker, string) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewBroker()
+	served := make(chan error, 1)
+	go func() {
+		served <- b.Serve(lis)
+	}()
+	t.Cleanup(func() {
+		b.Close()
+		if err := <-served; err != ErrClosed {
+			t.Errorf("Serve returned %v, want ErrClosed", err)
+		}
+	})
+
+	return b, lis.Addr().String()
+}
+
+// newRedis connects to the broker at addr until the test ends.
+func newRedis(t *testing.T, addr string) *Redis {
+	t.Helper()
+
+	r := NewRedis(addr, "secret")
+	t.Cleanup(func() {
+		r.Close()
+	})
+
+	return r
+}
+
+// collect subscribes to subject, returning the events it receives.
+func collect(t *testing.T, r *Redis, subject string) <-chan string {
+	t.Helper()
+
+	events := make(chan string, 1000)
+	if err := r.Subscribe(subject, func(data []byte) {
+		events <- string(data)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	return events
+}
+
+// waitFor fails the test if cond does not hold within waitTimeout.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(waitTimeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// subscribers returns how many connections to b are subscribed to subject.
+func (b *Broker) subscribers(subject string) int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return len(b.subs[subject])
+}
+
+// connections returns how many clients are connected to b.
+func (b *Broker) connections() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return len(b.conns)
+}
+
+// drop disconnects every client of b, as if the connections had failed.
+func (b *Broker) drop() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for c := range b.conns {
+		c.conn.Close()
+	}
+}
+
+// expect fails the test unless events receives want next.
+func expect(t *testing.T, events <-chan string, want string) {
+	t.Helper()
+
+	select {
+	case got := <-events:
+		if got != want {
+			t.Fatalf("received %q, want %q", got, want)
+		}
+	case <-time.After(waitTimeout):
+		t.Fatalf("timed out waiting for %q", want)
+	}
+}
+
+// TestRedisPublishSubscribe publishes from one replica to two, the publisher
+// included, which must each receive every event in order and none of another
+// subject's.
+func TestRedisPublishSubscribe(t *testing.T) {
+	b, addr := startBroker(t)
+	pub, other := newRedis(t, addr), newRedis(t, addr)
+	pubEvents := collect(t, pub, "rooms.a")
+	otherEvents := collect(t, other, "rooms.a")
+	elsewhere := collect(t, other, "rooms.b")
+	waitFor(t, "both replicas to subscribe", func() bool {
+		return b.subscribers("rooms.a") == 2 && b.subscribers("rooms.b") == 1
+	})
+
+	const n = 200
+	for i := 0; i < n; i++ {
+		if err := pub.Publish("rooms.a", []byte(strconv.Itoa(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < n; i++ {
+		expect(t, pubEvents, strconv.Itoa(i))
+		expect(t, otherEvents, strconv.Itoa(i))
+	}
+
+	select {
+	case got := <-elsewhere:
+		t.Fatalf("rooms.b received %q published to rooms.a", got)
+	default:
+	}
+}
+
+// TestRedisResubscribe drops every connection to the broker. The subscriber
+// must come back subscribed to each subject it had, and publishing must
+// redial.
+func TestRedisResubscribe(t *testing.T) {
+	b, addr := startBroker(t)
+	r := newRedis(t, addr)
+	a, c := collect(t, r, "rooms.a"), collect(t, r, "rooms.c")
+	waitFor(t, "the replica to subscribe", func() bool {
+		return b.subscribers("rooms.a") == 1 && b.subscribers("rooms.c") == 1
+	})
+	if err := r.Publish("rooms.a", []byte("before")); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, a, "before")
+
+	b.drop()
+	// The replica waits a backoff before reconnecting, long enough for the
+	// broker to let go of the old connections first.
+	waitFor(t, "the connections to drop", func() bool {
+		return b.connections() == 0
+	})
+	waitFor(t, "the replica to resubscribe", func() bool {
+		return b.connections() == 1 && b.subscribers("rooms.a") == 1 && b.subscribers("rooms.c") == 1
+	})
+
+	// The first publish may find the old connection gone, failing before the
+	// next one redials.
+	waitFor(t, "a publish to succeed", func() bool {
+		return r.Publish("rooms.a", []byte("after")) == nil
+	})
+	expect(t, a, "after")
+	if err := r.Publish("rooms.c", []byte("after")); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, c, "after")
+}
+
+// TestRedisClose closes a replica, which must disconnect, refuse to publish
+// or subscribe, and receive nothing more.
+func TestRedisClose(t *testing.T) {
+	b, addr := startBroker(t)
+	r := newRedis(t, addr)
+	events := collect(t, r, "rooms.a")
+	waitFor(t, "the replica to subscribe", func() bool {
+		return b.subscribers("rooms.a") == 1
+	})
+	if err := r.Publish("rooms.a", []byte("open")); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, events, "open")
+
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "the replica to disconnect", func() bool {
+		return b.connections() == 0
+	})
+	if err := r.Publish("rooms.a", []byte("closed")); err != ErrClosed {
+		t.Errorf("Publish after Close returned %v, want ErrClosed", err)
+	}
+	if err := r.Subscribe("rooms.b", func([]byte) {}); err != ErrClosed {
+		t.Errorf("Subscribe after Close returned %v, want ErrClosed", err)
+	}
+
+	other := newRedis(t, addr)
+	if err := other.Publish("rooms.a", []byte("closed")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-events:
+		t.Fatalf("received %q after Close", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestBrokerClose closes the broker under a replica, which must be
+// disconnected and keep failing to reconnect.
+func TestBrokerClose(t *testing.T) {
+	b, addr := startBroker(t)
+	r := newRedis(t, addr)
+	collect(t, r, "rooms.a")
+	waitFor(t, "the replica to subscribe", func() bool {
+		return b.subscribers("rooms.a") == 1
+	})
+
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "the replica to be disconnected", func() bool {
+		return b.connections() == 0
+	})
+	if err := r.Publish("rooms.a", []byte("closed")); err == nil {
+		t.Error("Publish to a closed broker succeeded")
+	}
+	if err := b.Serve(nil); err != ErrClosed {
+		t.Errorf("Serve after Close returned %v, want ErrClosed", err)
+	}
+}
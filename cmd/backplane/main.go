@@ -0,0 +1,38 @@
+// Command backplane runs a stand-in for Redis pub/sub, for Chat replicas to
+// share rooms over without a Redis server. Point BACKPLANE_URL at it with
+// redis://localhost:6379.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/richardjaytea/infipic/backplane"
+	"github.com/richardjaytea/infipic/config"
+	"github.com/richardjaytea/infipic/shutdown"
+)
+
+var conf = config.DefaultBroker()
+
+func main() {
+	loader := config.NewLoader(flag.CommandLine, &conf)
+	flag.Parse()
+	if err := loader.Load(); err != nil {
+		log.Fatalf("invalid config: %v", err)
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", conf.Port))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	b := backplane.NewBroker()
+	serve := func() error {
+		return b.Serve(lis)
+	}
+	if err := shutdown.ServeCloser("Backplane", serve, b); err != nil {
+		log.Fatalf("failed to serve Backplane: %v", err)
+	}
+}
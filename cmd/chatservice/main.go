@@ -0,0 +1,100 @@
+package main
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/richardjaytea/infipic/config"
+	"github.com/richardjaytea/infipic/healthcheck"
+	"github.com/richardjaytea/infipic/metrics"
+	"github.com/richardjaytea/infipic/mtls"
+	"github.com/richardjaytea/infipic/pb"
+	"github.com/richardjaytea/infipic/shutdown"
+	"github.com/richardjaytea/infipic/src/chatservice"
+	"google.golang.org/grpc"
+)
+
+var (
+	conf = config.DefaultChat()
+	// confLock guards the fields of conf that are reloaded while running.
+	confLock sync.Mutex
+)
+
+// serveWebSockets serves WebSocket players on HTTP_PORT, over TLS with certs
+// when the gRPC server uses it.
+func serveWebSockets(s *chatservice.Server, certs *mtls.Source) *http.Server {
+	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", conf.HTTPPort))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+	if certs != nil {
+		lis = tls.NewListener(lis, certs.HTTPConfig())
+	}
+
+	srv := &http.Server{Handler: s.WebSocketHandler(nil)}
+	go func() {
+		if err := srv.Serve(lis); err != http.ErrServerClosed {
+			log.Fatalf("failed to serve WebSockets: %v", err)
+		}
+	}()
+
+	return srv
+}
+
+func main() {
+	loader := config.NewLoader(flag.CommandLine, &conf)
+	flag.Parse()
+	if err := loader.Load(); err != nil {
+		log.Fatalf("invalid config: %v", err)
+	}
+	chatservice.Configure(conf)
+
+	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", conf.Server.Port))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+	opts := conf.Keepalive.ServerOptions()
+	var certs *mtls.Source
+	if conf.TLS {
+		certs, err = mtls.Load(conf.CertFile, conf.KeyFile, conf.CAFile, conf.TrustDomain)
+		if err != nil {
+			log.Fatalf("Failed to load TLS certificates: %v", err)
+		}
+		opts = append(opts, grpc.Creds(certs.ServerCredentials(conf.AllowedClients)))
+	}
+	hs := healthcheck.NewServer()
+	s := chatservice.New(hs)
+	grpcServer := grpc.NewServer(append(opts, s.ServerOptions()...)...)
+	hs.Register(grpcServer)
+	pb.RegisterChatServer(grpcServer, s)
+
+	loader.Watch(&confLock, func() {
+		chatservice.Configure(conf)
+	})
+	go s.Start()
+
+	closers := []io.Closer{s}
+	if certs != nil {
+		closers = append(closers, certs)
+	}
+	if conf.HTTPPort > 0 {
+		closers = append([]io.Closer{serveWebSockets(s, certs)}, closers...)
+	}
+	if conf.MetricsPort > 0 {
+		closers = append([]io.Closer{metrics.Serve(conf.MetricsPort)}, closers...)
+	}
+
+	drain := func() {
+		hs.Shutdown()
+		s.Drain()
+	}
+	if err := shutdown.Serve("Chat", grpcServer, lis, conf.ShutdownTimeout, drain, closers...); err != nil {
+		log.Fatalf("failed to serve Chat: %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+// Command gateway serves the split services to browsers as JSON over HTTP.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/richardjaytea/infipic/config"
+	"github.com/richardjaytea/infipic/discovery"
+	"github.com/richardjaytea/infipic/gateway"
+	"github.com/richardjaytea/infipic/metrics"
+	"github.com/richardjaytea/infipic/mtls"
+	"github.com/richardjaytea/infipic/shutdown"
+	"google.golang.org/grpc"
+)
+
+var conf = config.DefaultGateway()
+
+func dial(conn *grpc.ClientConn, err error) *grpc.ClientConn {
+	if err != nil {
+		log.Fatalf("fail to dial: %v", err)
+	}
+
+	return conn
+}
+
+func main() {
+	loader := config.NewLoader(flag.CommandLine, &conf)
+	flag.Parse()
+	if err := loader.Load(); err != nil {
+		log.Fatalf("invalid config: %v", err)
+	}
+
+	// The gateway presents its certificate to the services, and serves
+	// browsers HTTPS with it.
+	var certs *mtls.Source
+	creds := func(string) grpc.DialOption {
+		return grpc.WithInsecure()
+	}
+	if conf.TLS {
+		var err error
+		certs, err = mtls.Load(conf.CertFile, conf.KeyFile, conf.CAFile, conf.TrustDomain)
+		if err != nil {
+			log.Fatalf("Failed to load TLS certificates: %v", err)
+		}
+		creds = func(service string) grpc.DialOption {
+			return grpc.WithTransportCredentials(certs.ClientCredentials(service))
+		}
+	}
+	// A player's Chat calls must all reach the replica holding their stream, so
+	// the gateway keeps to one Chat replica.
+	conns := gateway.Conns{
+		Auth:  dial(discovery.Dial(conf.ServerAddrAuth, "pb.Auth", creds("auth"))),
+		Room:  dial(discovery.Dial(conf.ServerAddrRoom, "pb.Room", creds("room"))),
+		Chat:  dial(discovery.DialSticky(conf.ServerAddrChat, creds("chat"))),
+		Image: dial(discovery.Dial(conf.ServerAddrImage, "pb.Image", creds("image"))),
+	}
+
+	h, err := gateway.New(context.Background(), conns)
+	if err != nil {
+		log.Fatalf("failed to create gateway: %v", err)
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", conf.Server.Port))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+	if certs != nil {
+		lis = tls.NewListener(lis, certs.HTTPConfig())
+	}
+
+	srv := &http.Server{Handler: gateway.AllowOrigins(h, conf.AllowedOrigins)}
+	closers := []io.Closer{conns.Auth, conns.Room, conns.Chat, conns.Image}
+	if certs != nil {
+		closers = append(closers, certs)
+	}
+	if conf.MetricsPort > 0 {
+		closers = append([]io.Closer{metrics.Serve(conf.MetricsPort)}, closers...)
+	}
+	if err := shutdown.ServeHTTP("Gateway", srv, lis, conf.ShutdownTimeout, closers...); err != nil {
+		log.Fatalf("failed to serve Gateway: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+
+	"github.com/richardjaytea/infipic/config"
+	"github.com/richardjaytea/infipic/healthcheck"
+	"github.com/richardjaytea/infipic/metrics"
+	"github.com/richardjaytea/infipic/mtls"
+	"github.com/richardjaytea/infipic/pb"
+	"github.com/richardjaytea/infipic/shutdown"
+	"github.com/richardjaytea/infipic/src/imageservice"
+	"google.golang.org/grpc"
+)
+
+var (
+	conf = config.DefaultImage()
+	// confLock guards the fields of conf that are reloaded while running.
+	confLock sync.Mutex
+)
+
+func main() {
+	loader := config.NewLoader(flag.CommandLine, &conf)
+	flag.Parse()
+	if err := loader.Load(); err != nil {
+		log.Fatalf("invalid config: %v", err)
+	}
+	imageservice.Configure(conf)
+
+	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", conf.Server.Port))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+	opts := conf.Keepalive.ServerOptions()
+	var certs *mtls.Source
+	if conf.TLS {
+		certs, err = mtls.Load(conf.CertFile, conf.KeyFile, conf.CAFile, conf.TrustDomain)
+		if err != nil {
+			log.Fatalf("Failed to load TLS certificates: %v", err)
+		}
+		opts = append(opts, grpc.Creds(certs.ServerCredentials(conf.AllowedClients)))
+	}
+	grpcServer := grpc.NewServer(opts...)
+	hs := healthcheck.NewServer()
+	hs.Register(grpcServer)
+	s := imageservice.New(hs)
+	pb.RegisterImageServer(grpcServer, s)
+
+	loader.Watch(&confLock, func() {
+		imageservice.Configure(conf)
+		s.ScheduleRounds()
+	})
+	go s.Start()
+
+	closers := []io.Closer{s}
+	if certs != nil {
+		closers = append(closers, certs)
+	}
+	if conf.MetricsPort > 0 {
+		closers = append([]io.Closer{metrics.Serve(conf.MetricsPort)}, closers...)
+	}
+
+	drain := func() {
+		hs.Shutdown()
+		s.Drain()
+	}
+	if err := shutdown.Serve("Image", grpcServer, lis, conf.ShutdownTimeout, drain, closers...); err != nil {
+		log.Fatalf("failed to serve Image: %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+// Command pictionary runs Auth, Room, Image and Chat in one process, serving
+// them on one port. The services reach each other over in-process connections rather
+// than the network, served without TLS by a grpc.Server of their own. Room is
+// only served there, as in split mode it only takes calls from the other
+// services, and players could otherwise ban each other through AddBan. The
+// HTTP gateway and WebSockets for browsers are served on HTTP_PORT.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/richardjaytea/infipic/config"
+	"github.com/richardjaytea/infipic/gateway"
+	"github.com/richardjaytea/infipic/healthcheck"
+	"github.com/richardjaytea/infipic/metrics"
+	"github.com/richardjaytea/infipic/mtls"
+	"github.com/richardjaytea/infipic/pb"
+	"github.com/richardjaytea/infipic/shutdown"
+	"github.com/richardjaytea/infipic/src/authservice"
+	"github.com/richardjaytea/infipic/src/chatservice"
+	"github.com/richardjaytea/infipic/src/imageservice"
+	"github.com/richardjaytea/infipic/src/roomservice"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// inProcess is the address services dial each other on. The dialer ignores it
+// and connects to the in-process listener.
+const inProcess = "in-process"
+
+var (
+	conf = config.DefaultAll()
+	// confLock guards the fields of conf that are reloaded while running.
+	confLock sync.Mutex
+)
+
+// closerFunc closes by calling f.
+type closerFunc func()
+
+func (f closerFunc) Close() error {
+	f()
+	return nil
+}
+
+// configure hands each service its part of conf.
+func configure() {
+	chat := conf.Chat
+	chat.ServerAddrRoom, chat.ServerAddrImage = inProcess, inProcess
+	chat.TLS = false
+	image := conf.Image()
+	image.ServerAddrRoom = inProcess
+	image.TLS = false
+	room := conf.Room()
+	room.TLS = false
+
+	authservice.Configure(conf.Auth())
+	roomservice.Configure(room)
+	imageservice.Configure(image)
+	chatservice.Configure(chat)
+}
+
+// serveGateway serves the HTTP gateway and chat's WebSockets on HTTP_PORT,
+// the gateway calling the services over an in-process connection.
+func serveGateway(chat *chatservice.Server, dialer grpc.DialOption) *http.Server {
+	conn, err := grpc.Dial(inProcess, dialer, grpc.WithInsecure())
+	if err != nil {
+		log.Fatalf("fail to dial: %v", err)
+	}
+
+	h, err := gateway.New(context.Background(), gateway.Conns{Auth: conn, Room: conn, Chat: conn, Image: conn})
+	if err != nil {
+		log.Fatalf("failed to create gateway: %v", err)
+	}
+	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", conf.HTTPPort))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	srv := &http.Server{Handler: gateway.AllowOrigins(chat.WebSocketHandler(h), conf.AllowedOrigins)}
+	go func() {
+		if err := srv.Serve(lis); err != http.ErrServerClosed {
+			log.Fatalf("failed to serve Gateway: %v", err)
+		}
+	}()
+	log.Printf("Serving HTTP gateway on %s", lis.Addr())
+
+	return srv
+}
+
+func main() {
+	loader := config.NewLoader(flag.CommandLine, &conf)
+	flag.Parse()
+	if err := loader.Load(); err != nil {
+		log.Fatalf("invalid config: %v", err)
+	}
+	configure()
+
+	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", conf.Server.Port))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+	opts := conf.Keepalive.ServerOptions()
+	var certs *mtls.Source
+	if conf.TLS {
+		certs, err = mtls.Load(conf.CertFile, conf.KeyFile, conf.CAFile, conf.TrustDomain)
+		if err != nil {
+			log.Fatalf("Failed to load TLS certificates: %v", err)
+		}
+	}
+
+	hs := healthcheck.NewServer()
+	auth := authservice.New(hs)
+	room := roomservice.New(hs)
+	image := imageservice.New(hs)
+	chat := chatservice.New(hs)
+	newServer := func(opts ...grpc.ServerOption) *grpc.Server {
+		g := grpc.NewServer(append(opts, chat.ServerOptions()...)...)
+		hs.Register(g)
+		pb.RegisterAuthServer(g, auth)
+		pb.RegisterImageServer(g, image)
+		pb.RegisterChatServer(g, chat)
+		return g
+	}
+	localServer := newServer()
+	pb.RegisterRoomServer(localServer, room)
+	if certs != nil {
+		opts = append(opts, grpc.Creds(certs.ServerCredentials(conf.AllowedClients)))
+	}
+	grpcServer := newServer(opts...)
+
+	loader.Watch(&confLock, func() {
+		configure()
+		image.ScheduleRounds()
+	})
+
+	local := bufconn.Listen(1 << 20)
+	go func() {
+		if err := localServer.Serve(local); err != nil {
+			log.Printf("In-process listener stopped: %v", err)
+		}
+	}()
+	dialer := grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return local.Dial()
+	})
+	go image.Start(dialer)
+	go chat.Start(dialer)
+
+	closers := []io.Closer{chat, image, closerFunc(localServer.GracefulStop), room.DB}
+	if certs != nil {
+		closers = append(closers, certs)
+	}
+	if conf.HTTPPort > 0 {
+		closers = append([]io.Closer{serveGateway(chat, dialer)}, closers...)
+	}
+	if conf.MetricsPort > 0 {
+		closers = append([]io.Closer{metrics.Serve(conf.MetricsPort)}, closers...)
+	}
+
+	drain := func() {
+		hs.Shutdown()
+		chat.Drain()
+		image.Drain()
+	}
+	if err := shutdown.Serve("Pictionary", grpcServer, lis, conf.ShutdownTimeout, drain, closers...); err != nil {
+		log.Fatalf("failed to serve Pictionary: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+
+	"github.com/richardjaytea/infipic/config"
+	"github.com/richardjaytea/infipic/healthcheck"
+	"github.com/richardjaytea/infipic/metrics"
+	"github.com/richardjaytea/infipic/mtls"
+	"github.com/richardjaytea/infipic/pb"
+	"github.com/richardjaytea/infipic/shutdown"
+	"github.com/richardjaytea/infipic/src/roomservice"
+	"google.golang.org/grpc"
+)
+
+var conf = config.DefaultRoom()
+
+func main() {
+	loader := config.NewLoader(flag.CommandLine, &conf)
+	flag.Parse()
+	if err := loader.Load(); err != nil {
+		log.Fatalf("invalid config: %v", err)
+	}
+	roomservice.Configure(conf)
+
+	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", conf.Server.Port))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+	opts := conf.Keepalive.ServerOptions()
+	var certs *mtls.Source
+	if conf.TLS {
+		certs, err = mtls.Load(conf.CertFile, conf.KeyFile, conf.CAFile, conf.TrustDomain)
+		if err != nil {
+			log.Fatalf("Failed to load TLS certificates: %v", err)
+		}
+		opts = append(opts, grpc.Creds(certs.ServerCredentials(conf.AllowedClients)))
+	}
+	grpcServer := grpc.NewServer(opts...)
+	hs := healthcheck.NewServer()
+	hs.Register(grpcServer)
+	s := roomservice.New(hs)
+	pb.RegisterRoomServer(grpcServer, s)
+
+	closers := []io.Closer{s.DB}
+	if certs != nil {
+		closers = append(closers, certs)
+	}
+	if conf.MetricsPort > 0 {
+		closers = append([]io.Closer{metrics.Serve(conf.MetricsPort)}, closers...)
+	}
+
+	if err := shutdown.Serve("Room", grpcServer, lis, conf.ShutdownTimeout, hs.Shutdown, closers...); err != nil {
+		log.Fatalf("failed to serve Room: %v", err)
+	}
+}
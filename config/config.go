@@ -1,24 +1,224 @@
 package config
 
 import (
-	"github.com/spf13/viper"
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
 )
 
-func VGetEnv(key string) string {
-	viper.SetConfigFile("../../.env")
+// DefaultFile is looked for in the working directory and its parents when no
+// config file is given.
+const DefaultFile = ".env"
+
+// Validator is implemented by every service config.
+type Validator interface {
+	Validate() error
+}
+
+type field struct {
+	key    string
+	usage  string
+	reload bool
+	index  []int
+	typ    reflect.Type
+}
 
-	err := viper.ReadInConfig()
+// flagValue records a config flag so it only overrides the other layers when
+// it was set on the command line.
+type flagValue struct {
+	value  string
+	isBool bool
+}
+
+func (f *flagValue) String() string     { return f.value }
+func (f *flagValue) Set(s string) error { f.value = s; return nil }
+func (f *flagValue) IsBoolFlag() bool   { return f.isBool }
+
+// Loader fills a service config from, in increasing priority, the defaults it
+// starts with, a config file, environment variables and command line flags.
+type Loader struct {
+	cfg    Validator
+	fields []field
+	flags  map[string]*flagValue
+	path   *string
+	fs     *flag.FlagSet
+	v      *viper.Viper
+}
+
+// NewLoader registers a flag on fs for every field of cfg, which must be a
+// pointer to a struct with mapstructure tags. Flags are named after the key in
+// lower case, plus -config for the config file. Call it before fs is parsed.
+func NewLoader(fs *flag.FlagSet, cfg Validator) *Loader {
+	l := &Loader{
+		cfg:    cfg,
+		fields: fields(reflect.TypeOf(cfg).Elem(), nil),
+		flags:  make(map[string]*flagValue),
+		path:   fs.String("config", "", "The config file, defaults to the nearest "+DefaultFile),
+		fs:     fs,
+		v:      viper.New(),
+	}
 
+	value := reflect.ValueOf(cfg).Elem()
+	for _, f := range l.fields {
+		def := value.FieldByIndex(f.index).Interface()
+		fv := &flagValue{value: fmt.Sprint(def), isBool: f.typ.Kind() == reflect.Bool}
+		l.flags[f.key] = fv
+		fs.Var(fv, strings.ToLower(f.key), f.usage)
+		l.v.SetDefault(f.key, def)
+	}
+
+	return l
+}
+
+// Load reads every layer into the config and validates it.
+func (l *Loader) Load() error {
+	path := *l.path
+	if path == "" {
+		path = findFile(DefaultFile)
+	}
+	if path != "" {
+		l.v.SetConfigFile(path)
+		if err := l.v.ReadInConfig(); err != nil {
+			return fmt.Errorf("read config file %s: %w", path, err)
+		}
+		log.Printf("Loaded config from %s", path)
+	}
+
+	l.v.AutomaticEnv()
+
+	l.fs.Visit(func(f *flag.Flag) {
+		if fv, ok := l.flags[strings.ToUpper(f.Name)]; ok {
+			l.v.Set(f.Name, fv.value)
+		}
+	})
+
+	if err := l.v.Unmarshal(l.cfg); err != nil {
+		return fmt.Errorf("parse config: %w", err)
+	}
+
+	return l.cfg.Validate()
+}
+
+// Watch reloads the config file when it changes. Fields tagged reload:"true"
+// are copied into the config while holding lock, and onReload, if not nil, is
+// called when any of them changed. Reloads that fail validation are ignored.
+func (l *Loader) Watch(lock sync.Locker, onReload func()) {
+	if l.v.ConfigFileUsed() == "" {
+		return
+	}
+
+	l.v.OnConfigChange(func(e fsnotify.Event) {
+		fresh := reflect.New(reflect.TypeOf(l.cfg).Elem())
+		if err := l.v.Unmarshal(fresh.Interface()); err != nil {
+			log.Printf("Ignoring config change, %v", err)
+			return
+		}
+		if err := fresh.Interface().(Validator).Validate(); err != nil {
+			log.Printf("Ignoring invalid config change, %v", err)
+			return
+		}
+
+		changed := false
+		lock.Lock()
+		current := reflect.ValueOf(l.cfg).Elem()
+		for _, f := range l.fields {
+			from, to := fresh.Elem().FieldByIndex(f.index), current.FieldByIndex(f.index)
+			if f.reload && !reflect.DeepEqual(from.Interface(), to.Interface()) {
+				log.Printf("Reloaded %s = %v", f.key, from.Interface())
+				to.Set(from)
+				changed = true
+			}
+		}
+		lock.Unlock()
+
+		if changed && onReload != nil {
+			onReload()
+		}
+	})
+	l.v.WatchConfig()
+}
+
+// fields lists the config fields of t, going into structs tagged squash.
+func fields(t reflect.Type, index []int) []field {
+	var fs []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("mapstructure")
+		idx := append(append([]int{}, index...), i)
+
+		if strings.HasSuffix(tag, ",squash") {
+			fs = append(fs, fields(sf.Type, idx)...)
+			continue
+		}
+		if tag == "" {
+			continue
+		}
+
+		fs = append(fs, field{
+			key:    tag,
+			usage:  sf.Tag.Get("usage"),
+			reload: sf.Tag.Get("reload") == "true",
+			index:  idx,
+			typ:    sf.Type,
+		})
+	}
+
+	return fs
+}
+
+// findFile returns the path of name in the working directory or the closest
+// parent that has it, or "" if there is none.
+func findFile(name string) string {
+	dir, err := os.Getwd()
 	if err != nil {
-		log.Fatalf("Error while reading config file %s", err)
+		return ""
+	}
+
+	for {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func positive(key string, v float64) error {
+	if v <= 0 {
+		return fmt.Errorf("%s must be positive", key)
+	}
+
+	return nil
+}
+
+func oneOf(key, v string, allowed ...string) error {
+	for _, a := range allowed {
+		if v == a {
+			return nil
+		}
 	}
 
-	value, ok := viper.Get(key).(string)
+	return fmt.Errorf("%s must be one of %s", key, strings.Join(allowed, ", "))
+}
 
-	if !ok {
-		log.Fatalf("Invalid type assertion")
+func atLeast(key string, v, min time.Duration) error {
+	if v < min {
+		return fmt.Errorf("%s must be at least %s", key, min)
 	}
 
-	return value
+	return nil
 }
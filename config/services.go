@@ -0,0 +1,494 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// Server holds the listening settings every service has.
+type Server struct {
+	Port            int           `mapstructure:"PORT" usage:"The server port"`
+	TLS             bool          `mapstructure:"TLS" usage:"Serve and call other services over mutual TLS if true, else plain TCP"`
+	CertFile        string        `mapstructure:"CERT_FILE" usage:"The TLS cert file, naming the service with a SPIFFE ID"`
+	KeyFile         string        `mapstructure:"KEY_FILE" usage:"The TLS key file"`
+	CAFile          string        `mapstructure:"CA_FILE" usage:"The file containing the CA certs services' certs are checked against"`
+	TrustDomain     string        `mapstructure:"TRUST_DOMAIN" usage:"The trust domain of the services' SPIFFE IDs, spiffe://<domain>/<service>"`
+	AllowedClients  string        `mapstructure:"ALLOWED_CLIENTS" usage:"Comma separated services allowed to call this one over TLS, empty to let anyone call without a cert"`
+	ShutdownTimeout time.Duration `mapstructure:"SHUTDOWN_TIMEOUT" usage:"How long to wait for open calls to finish on shutdown"`
+	MetricsPort     int           `mapstructure:"METRICS_PORT" usage:"The port to serve metrics on at /debug/vars, 0 to not serve them"`
+}
+
+// Dial holds the settings for connecting to other services.
+type Dial struct {
+	ReadyTimeout time.Duration `mapstructure:"READY_TIMEOUT" usage:"How long to wait at startup for the services this one depends on"`
+}
+
+// Keepalive holds how gRPC servers check that idle connections are still
+// there, closing half-open ones.
+type Keepalive struct {
+	Time    time.Duration `mapstructure:"KEEPALIVE_TIME" usage:"How long a connection can be idle before the server pings the client"`
+	Timeout time.Duration `mapstructure:"KEEPALIVE_TIMEOUT" usage:"How long to wait for a ping to be answered before closing the connection"`
+	MinTime time.Duration `mapstructure:"KEEPALIVE_MIN_TIME" usage:"Clients pinging more often than this are disconnected"`
+}
+
+// ServerOptions returns the options applying k to a grpc.Server.
+func (k Keepalive) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{Time: k.Time, Timeout: k.Timeout}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{MinTime: k.MinTime, PermitWithoutStream: true}),
+	}
+}
+
+// Fanout holds how streams to slow clients are handled. Each stream has a queue
+// of messages waiting to be sent.
+type Fanout struct {
+	SendQueueSize      int    `mapstructure:"SEND_QUEUE_SIZE" usage:"Most messages waiting to be sent on a stream"`
+	SlowConsumerPolicy string `mapstructure:"SLOW_CONSUMER_POLICY" usage:"drop messages to streams with a full queue, or disconnect them"`
+}
+
+// DB holds the Postgres connection settings.
+type DB struct {
+	Host     string `mapstructure:"APP_DB_HOST" usage:"The database host"`
+	Port     int    `mapstructure:"APP_DB_PORT" usage:"The database port"`
+	Username string `mapstructure:"APP_DB_USERNAME" usage:"The database user"`
+	Password string `mapstructure:"APP_DB_PASSWORD" usage:"The database password"`
+	Name     string `mapstructure:"APP_DB_NAME" usage:"The database name"`
+}
+
+func (d DB) ConnectionString() string {
+	return fmt.Sprintf("host=%s port=%d user=%s "+
+		"password=%s dbname=%s sslmode=disable",
+		d.Host, d.Port, d.Username, d.Password, d.Name)
+}
+
+type Auth struct {
+	Server     `mapstructure:",squash"`
+	Keepalive  `mapstructure:",squash"`
+	AuthSecret string `mapstructure:"AUTH_SECRET" usage:"Shared by Auth, Chat and Image to sign and check client tokens"`
+}
+
+type Room struct {
+	Server    `mapstructure:",squash"`
+	Keepalive `mapstructure:",squash"`
+	DB        `mapstructure:",squash"`
+}
+
+type Image struct {
+	Server         `mapstructure:",squash"`
+	Keepalive      `mapstructure:",squash"`
+	Dial           `mapstructure:",squash"`
+	DB             `mapstructure:",squash"`
+	AuthSecret     string `mapstructure:"AUTH_SECRET" usage:"Shared by Auth, Chat and Image to sign and check client tokens"`
+	ServerAddrRoom string `mapstructure:"SERVER_ADDR_ROOM" usage:"Where to find the room service: host:port, a comma separated list, srv://name or file:///path"`
+	Round          `mapstructure:",squash"`
+	Fanout         `mapstructure:",squash"`
+}
+
+// Round holds the settings for picking each round's image and words.
+type Round struct {
+	RoundInterval        time.Duration `mapstructure:"ROUND_INTERVAL" reload:"true" usage:"How often rooms get a new image"`
+	KeywordMinConfidence float64       `mapstructure:"KEYWORD_MIN_CONFIDENCE" reload:"true" usage:"Lowest AI confidence for a keyword to be used as a word"`
+	KeywordsPerImage     int           `mapstructure:"KEYWORDS_PER_IMAGE" reload:"true" usage:"Most words to use for an image"`
+}
+
+type Chat struct {
+	Server          `mapstructure:",squash"`
+	Keepalive       `mapstructure:",squash"`
+	Dial            `mapstructure:",squash"`
+	Fanout          `mapstructure:",squash"`
+	AuthSecret      string `mapstructure:"AUTH_SECRET" usage:"Shared by Auth, Chat and Image to sign and check client tokens"`
+	ServerAddrImage string `mapstructure:"SERVER_ADDR_IMAGE" usage:"Where to find the image service: host:port, a comma separated list, srv://name or file:///path"`
+	ServerAddrRoom  string `mapstructure:"SERVER_ADDR_ROOM" usage:"Where to find the room service: host:port, a comma separated list, srv://name or file:///path"`
+	SysChatName     string `mapstructure:"SYS_CHAT_NAME" usage:"The name system messages are sent as"`
+	AdminToken      string `mapstructure:"ADMIN_TOKEN" usage:"Callers sending this in the x-admin-token metadata can moderate any room"`
+	HTTPPort        int    `mapstructure:"HTTP_PORT" usage:"The port to serve WebSockets on, 0 to not serve them"`
+	AllowedOrigins  string `mapstructure:"ALLOWED_ORIGINS" usage:"Comma separated origins whose pages may connect, or *"`
+	TrustedProxies  string `mapstructure:"TRUSTED_PROXIES" usage:"Comma separated IP addresses of proxies, such as the gateway, whose X-Forwarded-For gives players' addresses. Only list ones players can not call Chat from"`
+	BackplaneURL    string `mapstructure:"BACKPLANE_URL" usage:"redis://host:port of the backplane replicas share rooms over, empty for a single replica"`
+
+	ProfanityListFile string `mapstructure:"PROFANITY_LIST_FILE" usage:"File with one word per line to filter from chat"`
+	ProfanityAction   string `mapstructure:"PROFANITY_ACTION" reload:"true" usage:"mask or reject messages containing a listed word"`
+	LeakPolicy        string `mapstructure:"LEAK_POLICY" reload:"true" usage:"mask, block or solvers for messages revealing an answer"`
+
+	MessageRatePerSecond float64 `mapstructure:"MESSAGE_RATE_PER_SECOND" usage:"Messages a player can send a second"`
+	MessageBurst         float64 `mapstructure:"MESSAGE_BURST" usage:"Messages a player can send at once"`
+	GuessRatePerSecond   float64 `mapstructure:"GUESS_RATE_PER_SECOND" usage:"Single word messages a player can send a second"`
+	GuessBurst           float64 `mapstructure:"GUESS_BURST" usage:"Single word messages a player can send at once"`
+
+	GuessLimitPerRound        int           `mapstructure:"GUESS_LIMIT_PER_ROUND" reload:"true" usage:"Guesses a player can make each round"`
+	WrongGuessStreak          int           `mapstructure:"WRONG_GUESS_STREAK" reload:"true" usage:"Wrong guesses in a row before a cooldown"`
+	GuessCooldown             time.Duration `mapstructure:"GUESS_COOLDOWN" reload:"true" usage:"How long guesses are refused after too many wrong ones"`
+	BruteForceWindow          time.Duration `mapstructure:"BRUTE_FORCE_WINDOW" reload:"true" usage:"Window for counting different wrong guesses"`
+	BruteForceDistinctGuesses int           `mapstructure:"BRUTE_FORCE_DISTINCT_GUESSES" reload:"true" usage:"Different wrong guesses within the window that flag a player"`
+
+	ResumeGrace         time.Duration `mapstructure:"RESUME_GRACE" reload:"true" usage:"How long a player who dropped can resume their session, 0 to remove them straight away"`
+	HeartbeatTimeout    time.Duration `mapstructure:"HEARTBEAT_TIMEOUT" reload:"true" usage:"How long Play and WebSocket connections can send nothing before they are dropped, 0 to never drop them"`
+	AFKRounds           int           `mapstructure:"AFK_ROUNDS" reload:"true" usage:"Rounds a player can go without a guess, message or active heartbeat before they are marked AFK, 0 to never mark them"`
+	AFKDisconnectRounds int           `mapstructure:"AFK_DISCONNECT_ROUNDS" reload:"true" usage:"Rounds a player can be idle before they are disconnected, 0 to never disconnect them"`
+}
+
+// Broker is the config for the stand-in backplane broker.
+type Broker struct {
+	Port int `mapstructure:"PORT" usage:"The server port"`
+}
+
+// Gateway is the config for the HTTP gateway browsers use. PORT is the HTTP
+// port, and TLS serves HTTPS as well as dialing the services with mutual TLS,
+// using the same cert for both.
+type Gateway struct {
+	Server          `mapstructure:",squash"`
+	Dial            `mapstructure:",squash"`
+	ServerAddrAuth  string `mapstructure:"SERVER_ADDR_AUTH" usage:"Where to find the auth service: host:port, a comma separated list, srv://name or file:///path"`
+	ServerAddrRoom  string `mapstructure:"SERVER_ADDR_ROOM" usage:"Where to find the room service: host:port, a comma separated list, srv://name or file:///path"`
+	ServerAddrChat  string `mapstructure:"SERVER_ADDR_CHAT" usage:"Where to find the chat service: host:port, a comma separated list, srv://name or file:///path"`
+	ServerAddrImage string `mapstructure:"SERVER_ADDR_IMAGE" usage:"Where to find the image service: host:port, a comma separated list, srv://name or file:///path"`
+	AllowedOrigins  string `mapstructure:"ALLOWED_ORIGINS" usage:"Comma separated origins whose pages may call the gateway, or *"`
+}
+
+// All is the config for running every service in one process. The Chat
+// dial settings are used for the in-process connections between services,
+// and HTTP_PORT serves the HTTP gateway along with the WebSockets.
+type All struct {
+	Chat  `mapstructure:",squash"`
+	DB    `mapstructure:",squash"`
+	Round `mapstructure:",squash"`
+}
+
+func (a All) Auth() Auth {
+	return Auth{Server: a.Server, Keepalive: a.Keepalive, AuthSecret: a.AuthSecret}
+}
+
+func (a All) Room() Room {
+	return Room{Server: a.Server, Keepalive: a.Keepalive, DB: a.DB}
+}
+
+func (a All) Image() Image {
+	return Image{
+		Server:         a.Server,
+		Keepalive:      a.Keepalive,
+		Dial:           a.Dial,
+		DB:             a.DB,
+		AuthSecret:     a.AuthSecret,
+		ServerAddrRoom: a.ServerAddrRoom,
+		Round:          a.Round,
+		Fanout:         a.Fanout,
+	}
+}
+
+func defaultServer(port int, allowedClients string) Server {
+	return Server{
+		Port:            port,
+		TrustDomain:     "infipic.local",
+		AllowedClients:  allowedClients,
+		ShutdownTimeout: 10 * time.Second,
+	}
+}
+
+func defaultKeepalive() Keepalive {
+	return Keepalive{
+		Time:    time.Minute,
+		Timeout: 20 * time.Second,
+		MinTime: 10 * time.Second,
+	}
+}
+
+func defaultFanout() Fanout {
+	return Fanout{
+		SendQueueSize:      256,
+		SlowConsumerPolicy: "disconnect",
+	}
+}
+
+func defaultDB() DB {
+	return DB{
+		Host:     "127.0.0.1",
+		Port:     5432,
+		Username: "postgres",
+		Name:     "postgres",
+	}
+}
+
+func defaultDial() Dial {
+	return Dial{
+		ReadyTimeout: time.Minute,
+	}
+}
+
+func DefaultAuth() Auth {
+	return Auth{Server: defaultServer(10002, ""), Keepalive: defaultKeepalive()}
+}
+
+func DefaultRoom() Room {
+	return Room{Server: defaultServer(10003, "chat,image,gateway"), Keepalive: defaultKeepalive(), DB: defaultDB()}
+}
+
+func defaultRound() Round {
+	return Round{
+		RoundInterval:        30 * time.Second,
+		KeywordMinConfidence: 40,
+		KeywordsPerImage:     6,
+	}
+}
+
+func DefaultImage() Image {
+	return Image{
+		Server:         defaultServer(10001, "chat,gateway"),
+		Keepalive:      defaultKeepalive(),
+		Dial:           defaultDial(),
+		DB:             defaultDB(),
+		ServerAddrRoom: "localhost:10003",
+		Round:          defaultRound(),
+		Fanout:         defaultFanout(),
+	}
+}
+
+func DefaultChat() Chat {
+	return Chat{
+		Server:                    defaultServer(10000, ""),
+		Keepalive:                 defaultKeepalive(),
+		Dial:                      defaultDial(),
+		Fanout:                    defaultFanout(),
+		ServerAddrImage:           "localhost:10001",
+		ServerAddrRoom:            "localhost:10003",
+		SysChatName:               "*System*",
+		HTTPPort:                  8081,
+		ProfanityAction:           "mask",
+		LeakPolicy:                "mask",
+		MessageRatePerSecond:      1,
+		MessageBurst:              5,
+		GuessRatePerSecond:        0.5,
+		GuessBurst:                3,
+		GuessLimitPerRound:        30,
+		WrongGuessStreak:          6,
+		GuessCooldown:             10 * time.Second,
+		BruteForceWindow:          10 * time.Second,
+		BruteForceDistinctGuesses: 8,
+		ResumeGrace:               30 * time.Second,
+		HeartbeatTimeout:          45 * time.Second,
+		AFKRounds:                 2,
+		AFKDisconnectRounds:       5,
+	}
+}
+
+func DefaultBroker() Broker {
+	return Broker{Port: 6379}
+}
+
+func DefaultGateway() Gateway {
+	return Gateway{
+		Server:          defaultServer(8080, ""),
+		Dial:            defaultDial(),
+		ServerAddrAuth:  "localhost:10002",
+		ServerAddrRoom:  "localhost:10003",
+		ServerAddrChat:  "localhost:10000",
+		ServerAddrImage: "localhost:10001",
+	}
+}
+
+// DefaultAll listens where Chat does, as that is the port clients know, and
+// serves HTTP where the split gateway does.
+func DefaultAll() All {
+	chat := DefaultChat()
+	chat.HTTPPort = 8080
+
+	return All{
+		Chat:  chat,
+		DB:    defaultDB(),
+		Round: defaultRound(),
+	}
+}
+
+func (s Server) Validate() error {
+	if s.Port <= 0 || s.Port > 65535 {
+		return fmt.Errorf("PORT %d is out of range", s.Port)
+	}
+	if s.MetricsPort < 0 || s.MetricsPort > 65535 {
+		return fmt.Errorf("METRICS_PORT %d is out of range", s.MetricsPort)
+	}
+	if s.TLS && (s.CertFile == "" || s.KeyFile == "" || s.CAFile == "") {
+		return errors.New("TLS needs CERT_FILE, KEY_FILE and CA_FILE")
+	}
+	if s.TLS && s.TrustDomain == "" {
+		return errors.New("TLS needs a TRUST_DOMAIN")
+	}
+
+	return nil
+}
+
+func (k Keepalive) Validate() error {
+	if err := atLeast("KEEPALIVE_TIME", k.Time, time.Second); err != nil {
+		return err
+	}
+	if err := atLeast("KEEPALIVE_TIMEOUT", k.Timeout, time.Second); err != nil {
+		return err
+	}
+	if k.MinTime < 0 {
+		return fmt.Errorf("KEEPALIVE_MIN_TIME must not be negative, got %s", k.MinTime)
+	}
+
+	return nil
+}
+
+func (f Fanout) Validate() error {
+	if f.SendQueueSize <= 0 {
+		return errors.New("SEND_QUEUE_SIZE must be positive")
+	}
+
+	return oneOf("SLOW_CONSUMER_POLICY", f.SlowConsumerPolicy, "drop", "disconnect")
+}
+
+func (d Dial) Validate() error {
+	return atLeast("READY_TIMEOUT", d.ReadyTimeout, time.Second)
+}
+
+func (d DB) Validate() error {
+	if d.Host == "" || d.Name == "" {
+		return errors.New("APP_DB_HOST and APP_DB_NAME are required")
+	}
+
+	return nil
+}
+
+func (a *Auth) Validate() error {
+	if a.AuthSecret == "" {
+		return errors.New("AUTH_SECRET is required")
+	}
+	if err := a.Keepalive.Validate(); err != nil {
+		return err
+	}
+
+	return a.Server.Validate()
+}
+
+func (r *Room) Validate() error {
+	if err := r.Server.Validate(); err != nil {
+		return err
+	}
+	if err := r.Keepalive.Validate(); err != nil {
+		return err
+	}
+
+	return r.DB.Validate()
+}
+
+func (i *Image) Validate() error {
+	if i.AuthSecret == "" {
+		return errors.New("AUTH_SECRET is required")
+	}
+	if i.KeywordsPerImage <= 0 {
+		return errors.New("KEYWORDS_PER_IMAGE must be positive")
+	}
+	if err := atLeast("ROUND_INTERVAL", i.RoundInterval, 5*time.Second); err != nil {
+		return err
+	}
+	if err := i.Server.Validate(); err != nil {
+		return err
+	}
+	if err := i.Keepalive.Validate(); err != nil {
+		return err
+	}
+	if err := i.Fanout.Validate(); err != nil {
+		return err
+	}
+	if err := i.Dial.Validate(); err != nil {
+		return err
+	}
+
+	return i.DB.Validate()
+}
+
+func (c *Chat) Validate() error {
+	if c.AuthSecret == "" {
+		return errors.New("AUTH_SECRET is required")
+	}
+	if err := oneOf("PROFANITY_ACTION", c.ProfanityAction, "mask", "reject"); err != nil {
+		return err
+	}
+	if err := oneOf("LEAK_POLICY", c.LeakPolicy, "mask", "block", "solvers"); err != nil {
+		return err
+	}
+
+	for key, v := range map[string]float64{
+		"MESSAGE_RATE_PER_SECOND":      c.MessageRatePerSecond,
+		"MESSAGE_BURST":                c.MessageBurst,
+		"GUESS_RATE_PER_SECOND":        c.GuessRatePerSecond,
+		"GUESS_BURST":                  c.GuessBurst,
+		"GUESS_LIMIT_PER_ROUND":        float64(c.GuessLimitPerRound),
+		"WRONG_GUESS_STREAK":           float64(c.WrongGuessStreak),
+		"BRUTE_FORCE_WINDOW":           c.BruteForceWindow.Seconds(),
+		"BRUTE_FORCE_DISTINCT_GUESSES": float64(c.BruteForceDistinctGuesses),
+	} {
+		if err := positive(key, v); err != nil {
+			return err
+		}
+	}
+
+	for key, v := range map[string]float64{
+		"RESUME_GRACE":          c.ResumeGrace.Seconds(),
+		"HEARTBEAT_TIMEOUT":     c.HeartbeatTimeout.Seconds(),
+		"AFK_ROUNDS":            float64(c.AFKRounds),
+		"AFK_DISCONNECT_ROUNDS": float64(c.AFKDisconnectRounds),
+	} {
+		if v < 0 {
+			return fmt.Errorf("%s must not be negative", key)
+		}
+	}
+	if c.HeartbeatTimeout > 0 {
+		if err := atLeast("HEARTBEAT_TIMEOUT", c.HeartbeatTimeout, 3*time.Second); err != nil {
+			return err
+		}
+	}
+	if c.HTTPPort < 0 || c.HTTPPort > 65535 {
+		return fmt.Errorf("HTTP_PORT %d is out of range", c.HTTPPort)
+	}
+	for _, proxy := range strings.Split(c.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" && net.ParseIP(proxy) == nil {
+			return fmt.Errorf("TRUSTED_PROXIES has %q, which is not an IP address", proxy)
+		}
+	}
+	if c.BackplaneURL != "" && !strings.HasPrefix(c.BackplaneURL, "redis://") {
+		return fmt.Errorf("BACKPLANE_URL %q must start with redis://", c.BackplaneURL)
+	}
+	if err := c.Server.Validate(); err != nil {
+		return err
+	}
+	if err := c.Keepalive.Validate(); err != nil {
+		return err
+	}
+	if err := c.Fanout.Validate(); err != nil {
+		return err
+	}
+
+	return c.Dial.Validate()
+}
+
+func (b *Broker) Validate() error {
+	if b.Port <= 0 || b.Port > 65535 {
+		return fmt.Errorf("PORT %d is out of range", b.Port)
+	}
+
+	return nil
+}
+
+func (g *Gateway) Validate() error {
+	if err := g.Server.Validate(); err != nil {
+		return err
+	}
+
+	return g.Dial.Validate()
+}
+
+func (a *All) Validate() error {
+	if err := a.Chat.Validate(); err != nil {
+		return err
+	}
+
+	image := a.Image()
+	return image.Validate()
+}
@@ -0,0 +1,208 @@
+// Package discovery finds the replicas of a service a SERVER_ADDR_* setting
+// points at, and spreads calls over those that are healthy. A target is one
+// of:
+//
+//	host:port[,host:port...]  a fixed list of replicas
+//	srv://name                the replicas in name's DNS SRV records, looked
+//	                          up again every srvInterval
+//	file:///path              a registry file with a replica per line, read
+//	                          again when it changes
+package discovery
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	// Registers the client side health checking used by Dial.
+	_ "google.golang.org/grpc/health"
+	"google.golang.org/grpc/resolver"
+)
+
+// minResolveInterval keeps connection failures, which ask for the replicas to
+// be looked up again, from looking them up more often than this.
+const minResolveInterval = 5 * time.Second
+
+// Dial connects to every replica target finds, sending each call to the next
+// replica round robin. Replicas whose health check for service is not SERVING,
+// such as ones shutting down, are skipped until they are.
+func Dial(target, service string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	config := fmt.Sprintf(`{
+		"loadBalancingConfig": [{"round_robin": {}}],
+		"healthCheckConfig": {"serviceName": %q}
+	}`, service)
+
+	return dial(target, append(opts, grpc.WithDefaultServiceConfig(config)))
+}
+
+// DialSticky connects to the first replica target finds that accepts the
+// connection, moving to another only when it fails. It is for services whose
+// calls from one client must reach the same replica, such as Chat.
+func DialSticky(target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	return dial(target, opts)
+}
+
+func dial(target string, opts []grpc.DialOption) (*grpc.ClientConn, error) {
+	b, err := newBuilder(target)
+	if err != nil {
+		return nil, err
+	}
+
+	// The builder already knows where to look, so the endpoint is only used
+	// as the default authority.
+	return grpc.Dial(b.scheme+":///"+b.endpoint, append(opts, grpc.WithResolvers(b))...)
+}
+
+func newBuilder(target string) (builder, error) {
+	switch {
+	case strings.HasPrefix(target, "srv://"):
+		name := strings.TrimPrefix(target, "srv://")
+		if name == "" {
+			return builder{}, fmt.Errorf("discovery: no name in %q", target)
+		}
+		return builder{scheme: "srv", endpoint: name, build: func(cc resolver.ClientConn) (resolver.Resolver, error) {
+			return watch(cc, srvInterval, nil, func() ([]string, error) {
+				return lookupSRV(name)
+			}), nil
+		}}, nil
+	case strings.HasPrefix(target, "file://"):
+		return fileBuilder(strings.TrimPrefix(target, "file://"))
+	}
+
+	addrs := splitList(target, ",")
+	if len(addrs) == 0 {
+		return builder{}, fmt.Errorf("discovery: no addresses in %q", target)
+	}
+	return builder{scheme: "static", endpoint: target, build: func(cc resolver.ClientConn) (resolver.Resolver, error) {
+		cc.UpdateState(resolver.State{Addresses: addresses(addrs)})
+		return static{}, nil
+	}}, nil
+}
+
+// builder builds the resolver for a single Dial.
+type builder struct {
+	scheme   string
+	endpoint string
+	build    func(cc resolver.ClientConn) (resolver.Resolver, error)
+}
+
+func (b builder) Build(_ resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	return b.build(cc)
+}
+
+func (b builder) Scheme() string {
+	return b.scheme
+}
+
+// static resolves a fixed list of replicas, so has nothing to look up again.
+type static struct{}
+
+func (static) ResolveNow(resolver.ResolveNowOptions) {}
+
+func (static) Close() {}
+
+// watcher looks replicas up with lookup when started, every interval if it is
+// not 0, whenever changed receives, and when gRPC asks after a connection
+// fails. A failed lookup keeps the replicas found last.
+type watcher struct {
+	cc        resolver.ClientConn
+	lookup    func() ([]string, error)
+	interval  time.Duration
+	changed   <-chan struct{}
+	resolve   chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func watch(cc resolver.ClientConn, interval time.Duration, changed <-chan struct{}, lookup func() ([]string, error)) *watcher {
+	w := &watcher{
+		cc:       cc,
+		lookup:   lookup,
+		interval: interval,
+		changed:  changed,
+		resolve:  make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	go w.run()
+
+	return w
+}
+
+func (w *watcher) run() {
+	var tick <-chan time.Time
+	if w.interval > 0 {
+		t := time.NewTicker(w.interval)
+		defer t.Stop()
+		tick = t.C
+	}
+
+	for {
+		w.update()
+		last := time.Now()
+
+		select {
+		case <-w.done:
+			return
+		case <-tick:
+		case <-w.changed:
+		case <-w.resolve:
+			select {
+			case <-w.done:
+				return
+			case <-time.After(minResolveInterval - time.Since(last)):
+			}
+		}
+	}
+}
+
+func (w *watcher) update() {
+	addrs, err := w.lookup()
+	if err == nil && len(addrs) == 0 {
+		err = fmt.Errorf("discovery: no replicas found")
+	}
+	if err != nil {
+		w.cc.ReportError(err)
+		return
+	}
+
+	w.cc.UpdateState(resolver.State{Addresses: addresses(addrs)})
+}
+
+func (w *watcher) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case w.resolve <- struct{}{}:
+	default:
+	}
+}
+
+func (w *watcher) Close() {
+	w.closeOnce.Do(func() {
+		close(w.done)
+	})
+}
+
+// addresses returns the addresses of the replicas at addrs. Each is checked
+// against its own name when connecting over TLS.
+func addresses(addrs []string) []resolver.Address {
+	a := make([]resolver.Address, len(addrs))
+	for i, addr := range addrs {
+		a[i] = resolver.Address{Addr: addr, ServerName: addr}
+	}
+
+	return a
+}
+
+// splitList splits s by sep, leaving out blank items and # comments.
+func splitList(s, sep string) []string {
+	var items []string
+	for _, item := range strings.Split(s, sep) {
+		item = strings.TrimSpace(item)
+		if item != "" && !strings.HasPrefix(item, "#") {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,70 @@
+package discovery
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	"google.golang.org/grpc/resolver"
+)
+
+// fileBuilder reads the replicas from the registry file at path, one host:port
+// a line with # comments, and reads it again when it changes.
+func fileBuilder(path string) (builder, error) {
+	if path == "" {
+		return builder{}, fmt.Errorf("discovery: no path in file target")
+	}
+
+	return builder{scheme: "file", endpoint: path, build: func(cc resolver.ClientConn) (resolver.Resolver, error) {
+		fw, err := fsnotify.NewWatcher()
+		if err != nil {
+			return nil, err
+		}
+		// The directory is watched, as registries are often replaced by
+		// renaming a new file over them, which ends a watch on the file.
+		if err := fw.Add(filepath.Dir(path)); err != nil {
+			fw.Close()
+			return nil, err
+		}
+
+		changed := make(chan struct{}, 1)
+		w := watch(cc, 0, changed, func() ([]string, error) {
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			return splitList(string(b), "\n"), nil
+		})
+		go forwardChanges(fw, changed, w.done)
+
+		return w, nil
+	}}, nil
+}
+
+// forwardChanges signals changed for every change in the registry's
+// directory until done, then closes fw.
+func forwardChanges(fw *fsnotify.Watcher, changed chan<- struct{}, done <-chan struct{}) {
+	defer fw.Close()
+
+	for {
+		select {
+		case <-done:
+			return
+		case _, ok := <-fw.Events:
+			if !ok {
+				return
+			}
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		case err, ok := <-fw.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("Watching registry: %v", err)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// srvInterval is how often SRV records are looked up again, for replicas
+	// that were added or removed.
+	srvInterval = 30 * time.Second
+	srvTimeout  = 10 * time.Second
+)
+
+// lookupSRV returns the replicas in name's SRV records with the lowest
+// priority, the others being backups.
+func lookupSRV(name string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), srvTimeout)
+	defer cancel()
+
+	_, records, err := net.DefaultResolver.LookupSRV(ctx, "", "", name)
+	if err != nil {
+		return nil, err
+	}
+
+	var addrs []string
+	for _, r := range records {
+		// Records come sorted by priority.
+		if r.Priority != records[0].Priority {
+			break
+		}
+		host := strings.TrimSuffix(r.Target, ".")
+		addrs = append(addrs, net.JoinHostPort(host, strconv.Itoa(int(r.Port))))
+	}
+
+	return addrs, nil
+}
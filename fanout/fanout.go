@@ -0,0 +1,205 @@
+// Package fanout gives each subscriber of a broadcast its own bounded queue,
+// sent from by its own goroutine, so a slow subscriber only holds up itself.
+package fanout
+
+import (
+	"errors"
+	"expvar"
+	"log"
+	"sync"
+	"time"
+)
+
+// FlushTimeout is how long a closed queue keeps making the sends left in it,
+// so a notice queued just before closing still gets out.
+const FlushTimeout = time.Second
+
+var errStopped = errors.New("fanout: queue stopped")
+
+// What a full queue does with a new send.
+const (
+	// Drop drops the send, the subscriber misses it.
+	Drop = "drop"
+	// Disconnect closes the queue and tells the owner to disconnect the
+	// subscriber.
+	Disconnect = "disconnect"
+)
+
+// Group is the queues of one service, reported together in expvar under its
+// name.
+type Group struct {
+	lock         sync.Mutex
+	queues       map[*Queue]struct{}
+	dropped      expvar.Int
+	disconnected expvar.Int
+}
+
+// NewGroup returns a group published as name. Groups are meant to be package
+// variables, as expvar panics if a name is published twice.
+func NewGroup(name string) *Group {
+	g := &Group{queues: make(map[*Queue]struct{})}
+	expvar.Publish(name, expvar.Func(g.stats))
+	return g
+}
+
+// stats reports the number of queues, the sends waiting in all of them and in
+// the fullest, and how many sends were dropped and queues disconnected.
+func (g *Group) stats() interface{} {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	var depth, maxDepth int
+	for q := range g.queues {
+		n := len(q.sends)
+		depth += n
+		if n > maxDepth {
+			maxDepth = n
+		}
+	}
+
+	return map[string]int64{
+		"subscribers":  int64(len(g.queues)),
+		"depth":        int64(depth),
+		"max_depth":    int64(maxDepth),
+		"dropped":      g.dropped.Value(),
+		"disconnected": g.disconnected.Value(),
+	}
+}
+
+// Queue holds the sends to one subscriber until its goroutine makes them, in
+// the order they were pushed.
+type Queue struct {
+	group  *Group
+	sends  chan func() error
+	policy string
+	// slow is called once when the Disconnect policy closes the queue.
+	slow func()
+
+	stopOnce  sync.Once
+	stop      chan struct{}
+	abortOnce sync.Once
+	abort     chan struct{}
+	done      chan struct{}
+}
+
+// NewQueue starts a queue holding up to size sends. When it is full policy
+// decides what happens to the next, and slow is called if it disconnects.
+func (g *Group) NewQueue(size int, policy string, slow func()) *Queue {
+	q := &Queue{
+		group:  g,
+		sends:  make(chan func() error, size),
+		policy: policy,
+		slow:   slow,
+		stop:   make(chan struct{}),
+		abort:  make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	g.lock.Lock()
+	g.queues[q] = struct{}{}
+	g.lock.Unlock()
+
+	go q.run()
+	return q
+}
+
+// Push queues send without blocking. It returns false if the queue is closed
+// or full, in which case send is dropped.
+func (q *Queue) Push(send func() error) bool {
+	select {
+	case <-q.stop:
+		return false
+	default:
+	}
+
+	select {
+	case q.sends <- send:
+		return true
+	default:
+	}
+
+	if q.policy == Disconnect {
+		q.group.disconnected.Add(1)
+		// The subscriber is not keeping up, so what it has queued is
+		// dropped rather than flushed.
+		q.Close()
+		q.stopNow()
+		// Called on its own goroutine as Push is often called with the
+		// owner's locks held.
+		go q.slow()
+	} else {
+		q.group.dropped.Add(1)
+	}
+	return false
+}
+
+// Close stops the queue taking sends. The ones left in it are still made, for
+// up to FlushTimeout, then dropped. It does not wait for them, see Wait.
+func (q *Queue) Close() {
+	q.stopOnce.Do(func() {
+		close(q.stop)
+		time.AfterFunc(FlushTimeout, q.stopNow)
+
+		q.group.lock.Lock()
+		delete(q.group.queues, q)
+		q.group.lock.Unlock()
+	})
+}
+
+// stopNow drops the sends left in the queue.
+func (q *Queue) stopNow() {
+	q.abortOnce.Do(func() {
+		close(q.abort)
+	})
+}
+
+// Wait blocks until the queue is closed and its last send has returned.
+func (q *Queue) Wait() {
+	<-q.done
+}
+
+func (q *Queue) run() {
+	defer close(q.done)
+
+	for {
+		select {
+		case <-q.abort:
+			return
+		case <-q.stop:
+			q.flush()
+			return
+		case send := <-q.sends:
+			q.send(send)
+		}
+	}
+}
+
+// flush makes the sends left in a closed queue until it is empty or stopped,
+// or a send fails, as the subscriber is then most likely gone.
+func (q *Queue) flush() {
+	for {
+		select {
+		case send := <-q.sends:
+			if err := q.send(send); err != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// send makes send unless the queue has been stopped, logging its error.
+func (q *Queue) send(send func() error) error {
+	select {
+	case <-q.abort:
+		return errStopped
+	default:
+	}
+
+	err := send()
+	if err != nil {
+		log.Println(err)
+	}
+	return err
+}
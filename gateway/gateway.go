@@ -0,0 +1,114 @@
+// Package gateway serves the game to browsers as JSON over HTTP. Unary calls
+// are proxied by grpc-gateway using the routes annotated in services.proto,
+// and server streams are sent as server-sent events.
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/richardjaytea/infipic/auth"
+	"github.com/richardjaytea/infipic/pb"
+	"google.golang.org/grpc"
+)
+
+const (
+	adminTokenKey = "x-admin-token"
+	retryAfterKey = "retry-after"
+)
+
+// Conns are the connections to each service, which may all be the same.
+type Conns struct {
+	Auth  *grpc.ClientConn
+	Room  *grpc.ClientConn
+	Chat  *grpc.ClientConn
+	Image *grpc.ClientConn
+}
+
+// marshaler keeps the camelCase field names of the proto and writes zero
+// values, so a failed guess has "match": false rather than no field.
+var marshaler = &runtime.JSONPb{OrigName: true, EmitDefaults: true}
+
+// New returns the handler for every route. Clients send their token in the
+// X-Client-Token header, or as the token query parameter on streams as
+// EventSource can't set headers.
+func New(ctx context.Context, c Conns) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, marshaler),
+		runtime.WithIncomingHeaderMatcher(headerMatcher),
+		runtime.WithProtoErrorHandler(errorHandler),
+	)
+	if err := pb.RegisterAuthHandler(ctx, mux, c.Auth); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterRoomHandler(ctx, mux, c.Room); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterChatHandler(ctx, mux, c.Chat); err != nil {
+		return nil, err
+	}
+
+	streams := &streamHandler{
+		chat:  pb.NewChatClient(c.Chat),
+		image: pb.NewImageClient(c.Image),
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, streamSuffix) {
+			streams.ServeHTTP(w, r)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}), nil
+}
+
+// headerMatcher passes the client and admin tokens on as metadata.
+func headerMatcher(key string) (string, bool) {
+	switch k := strings.ToLower(key); k {
+	case auth.TokenKey, adminTokenKey:
+		return k, true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// errorHandler turns the retry-after trailer of rate limited calls into a
+// Retry-After header.
+func errorHandler(ctx context.Context, mux *runtime.ServeMux, m runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	if md, ok := runtime.ServerMetadataFromContext(ctx); ok {
+		if v := md.TrailerMD.Get(retryAfterKey); len(v) > 0 {
+			w.Header().Set("Retry-After", v[0])
+		}
+	}
+
+	runtime.DefaultHTTPProtoErrorHandler(ctx, mux, m, w, r, err)
+}
+
+// AllowOrigins lets pages from origins, a comma separated list or "*", call
+// h. Requests from other origins are served without CORS headers.
+func AllowOrigins(h http.Handler, origins string) http.Handler {
+	allowed := make(map[string]bool)
+	for _, o := range strings.Split(origins, ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			allowed[o] = true
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin != "" && (allowed["*"] || allowed[origin]) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+			w.Header().Set("Access-Control-Expose-Headers", "Retry-After")
+			if r.Method == http.MethodOptions {
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Client-Token, X-Admin-Token")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,175 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/richardjaytea/infipic/auth"
+	"github.com/richardjaytea/infipic/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const streamSuffix = "/stream"
+
+// streamHandler serves the server streams as server-sent events on
+//
+//	GET /v1/rooms/{roomKey}/messages/stream?id=&name=&team=&resume=  Chat.GetMessages
+//	GET /v1/rooms/{roomKey}/players/stream?id=                       Chat.GetPresence
+//	GET /v1/rooms/{roomKey}/image/stream?id=                         Image.GetImageAndWords
+//
+// Each response is a data event holding the JSON message. A failed call is an
+// error event holding the google.rpc.Status, after which the stream ends.
+type streamHandler struct {
+	chat  pb.ChatClient
+	image pb.ImageClient
+}
+
+func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if len(parts) != 5 || parts[0] != "v1" || parts[1] != "rooms" {
+		http.NotFound(w, r)
+		return
+	}
+	roomKey, kind := parts[2], parts[3]
+
+	q := r.URL.Query()
+	token := r.Header.Get(auth.TokenKey)
+	if token == "" {
+		token = q.Get("token")
+	}
+	ctx := auth.WithToken(r.Context(), token)
+	// grpc-gateway only forwards the client's address for unary calls, and
+	// Chat bans players by it.
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-forwarded-for", forwardedFor(r))
+	client := &pb.Client{Id: q.Get("id"), RoomKey: roomKey}
+
+	var next func() (proto.Message, error)
+	switch kind {
+	case "messages":
+		stream, err := h.chat.GetMessages(ctx, &pb.MessageStreamRequest{
+			Id:          client.Id,
+			RoomKey:     roomKey,
+			Name:        q.Get("name"),
+			Team:        q.Get("team"),
+			ResumeToken: q.Get("resume"),
+		})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		next = func() (proto.Message, error) { return stream.Recv() }
+	case "players":
+		stream, err := h.chat.GetPresence(ctx, client)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		next = func() (proto.Message, error) { return stream.Recv() }
+	case "image":
+		stream, err := h.image.GetImageAndWords(ctx, client)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		next = func() (proto.Message, error) { return stream.Recv() }
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	serveEvents(ctx, w, next)
+}
+
+// forwardedFor returns the request's X-Forwarded-For with the address it came
+// from added, as grpc-gateway does for unary calls.
+func forwardedFor(r *http.Request) string {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		return fwd + ", " + remote
+	}
+
+	return remote
+}
+
+// serveEvents writes every message from next as an event until the stream or
+// the request ends. Every stream sends a message straight away, so the first
+// one is waited for to answer a rejected call with its HTTP status instead.
+func serveEvents(ctx context.Context, w http.ResponseWriter, next func() (proto.Message, error)) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, status.Error(codes.Unimplemented, "streaming is not supported"))
+		return
+	}
+
+	m, err := next()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	for {
+		if err := writeEvent(w, "", m); err != nil {
+			log.Printf("Failed to write event: %v", err)
+			return
+		}
+		flusher.Flush()
+
+		m, err = next()
+		if err == io.EOF || ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			writeEvent(w, "error", status.Convert(err).Proto())
+			flusher.Flush()
+			return
+		}
+	}
+}
+
+// writeEvent writes m as an event of the given type, or a plain data event
+// when it is empty.
+func writeEvent(w io.Writer, event string, m proto.Message) error {
+	b, err := marshaler.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	if event != "" {
+		if _, err := fmt.Fprintf(w, "event: %s\n", event); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", b)
+	return err
+}
+
+// writeError answers a stream that failed before it started with the HTTP
+// status matching err.
+func writeError(w http.ResponseWriter, err error) {
+	s := status.Convert(err)
+	b, merr := marshaler.Marshal(s.Proto())
+	if merr != nil {
+		http.Error(w, s.Message(), runtime.HTTPStatusFromCode(s.Code()))
+		return
+	}
+
+	w.Header().Set("Content-Type", marshaler.ContentType())
+	w.WriteHeader(runtime.HTTPStatusFromCode(s.Code()))
+	w.Write(b)
+}
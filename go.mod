@@ -3,16 +3,18 @@ module github.com/richardjaytea/infipic
 go 1.15
 
 require (
+	github.com/fsnotify/fsnotify v1.4.7
 	github.com/golang/protobuf v1.4.3
 	github.com/google/uuid v1.2.0
+	github.com/gorilla/websocket v1.4.2
+	github.com/grpc-ecosystem/grpc-gateway v1.16.0
 	github.com/lib/pq v1.10.0
 	github.com/robfig/cron/v3 v3.0.0 // indirect
 	github.com/spf13/viper v1.7.1
 	golang.org/x/net v0.0.0-20210226172049-e18ecbb05110 // indirect
 	golang.org/x/sys v0.0.0-20210303074136-134d130e1a04 // indirect
 	golang.org/x/text v0.3.5 // indirect
-	google.golang.org/genproto v0.0.0-20210303154014-9728d6b83eeb // indirect
+	google.golang.org/genproto v0.0.0-20210303154014-9728d6b83eeb
 	google.golang.org/grpc v1.36.0
-	google.golang.org/grpc/examples v0.0.0-20210303003404-c275c3599a6f
 	google.golang.org/protobuf v1.25.0
 )
@@ -0,0 +1,179 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/richardjaytea/infipic/retry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// PollInterval is how often Poll checks a dependency.
+const PollInterval = 5 * time.Second
+
+var errNotChecked = errors.New("not checked yet")
+
+// Server is the grpc.health.v1 service for every service on a grpc.Server.
+// Each service is SERVING while all of its dependencies are up, and each
+// dependency is reported on its own as "<service>/<dependency>". The overall
+// "" status is SERVING while every service is.
+type Server struct {
+	hs *health.Server
+	// lock guards services and the dependencies of each service.
+	lock     sync.Mutex
+	services map[string]*Service
+}
+
+// Service tracks the dependencies of one service.
+type Service struct {
+	name   string
+	server *Server
+	deps   map[string]error
+	// optional are the dependencies the service carries on without.
+	optional map[string]bool
+}
+
+func NewServer() *Server {
+	return &Server{
+		hs:       health.NewServer(),
+		services: make(map[string]*Service),
+	}
+}
+
+func (s *Server) Register(g *grpc.Server) {
+	healthpb.RegisterHealthServer(g, s.hs)
+}
+
+// Service adds a service whose dependencies start down until they are Set.
+// A service without dependencies is SERVING straight away.
+func (s *Server) Service(name string, deps ...string) *Service {
+	svc := &Service{
+		name:     name,
+		server:   s,
+		deps:     make(map[string]error),
+		optional: make(map[string]bool),
+	}
+	for _, d := range deps {
+		svc.deps[d] = errNotChecked
+	}
+
+	s.lock.Lock()
+	s.services[name] = svc
+	s.update()
+	s.lock.Unlock()
+
+	return svc
+}
+
+// Optional adds dependencies the service carries on without, which start down
+// until they are Set. They are reported as "<service>/<dependency>" like the
+// others, but leave the service SERVING while they are down.
+func (svc *Service) Optional(deps ...string) {
+	s := svc.server
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, d := range deps {
+		svc.deps[d] = errNotChecked
+		svc.optional[d] = true
+	}
+	s.update()
+}
+
+// Shutdown reports every service as NOT_SERVING for good so clients stop
+// sending new calls while the server drains.
+func (s *Server) Shutdown() {
+	s.hs.Shutdown()
+}
+
+// update sets the status of every service, its dependencies and the overall
+// status. It must be called with s.lock held.
+func (s *Server) update() {
+	all := healthpb.HealthCheckResponse_SERVING
+	for _, svc := range s.services {
+		serving := healthpb.HealthCheckResponse_SERVING
+		for d, err := range svc.deps {
+			dep := healthpb.HealthCheckResponse_SERVING
+			if err != nil {
+				dep = healthpb.HealthCheckResponse_NOT_SERVING
+				if !svc.optional[d] {
+					serving = dep
+				}
+			}
+			s.hs.SetServingStatus(svc.name+"/"+d, dep)
+		}
+		if serving != healthpb.HealthCheckResponse_SERVING {
+			all = serving
+		}
+		s.hs.SetServingStatus(svc.name, serving)
+	}
+	s.hs.SetServingStatus("", all)
+}
+
+// Set records whether the dependency is up, err being why it is down.
+func (svc *Service) Set(dep string, err error) {
+	s := svc.server
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	prev, ok := svc.deps[dep]
+	if ok && (prev == nil) == (err == nil) {
+		svc.deps[dep] = err
+		return
+	}
+	svc.deps[dep] = err
+
+	if err != nil {
+		log.Printf("%s: %s is down: %v", svc.name, dep, err)
+	} else {
+		log.Printf("%s: %s is up", svc.name, dep)
+	}
+	s.update()
+}
+
+// Poll sets the dependency from check every PollInterval until ctx is done.
+func (svc *Service) Poll(ctx context.Context, dep string, check func(ctx context.Context) error) {
+	t := time.NewTicker(PollInterval)
+	defer t.Stop()
+
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, PollInterval)
+		svc.Set(dep, check(checkCtx))
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Check asks the server on conn whether service is SERVING.
+func Check(ctx context.Context, conn *grpc.ClientConn, service string) error {
+	r, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		return err
+	}
+	if r.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("%s is %s", service, r.Status)
+	}
+
+	return nil
+}
+
+// WaitReady waits until service on conn is SERVING, giving up after timeout.
+func WaitReady(conn *grpc.ClientConn, service string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return retry.Do(ctx, "wait for "+service, func() error {
+		return Check(ctx, conn, service)
+	})
+}
@@ -0,0 +1,31 @@
+// Package metrics serves the expvar variables of a service, including its
+// send queues, for scraping.
+package metrics
+
+import (
+	"expvar"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+)
+
+// Serve serves the variables at /debug/vars on port until the returned server
+// is closed.
+func Serve(port int) *http.Server {
+	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	srv := &http.Server{Handler: mux}
+	go func() {
+		if err := srv.Serve(lis); err != http.ErrServerClosed {
+			log.Fatalf("failed to serve metrics: %v", err)
+		}
+	}()
+
+	return srv
+}
@@ -12,250 +12,310 @@ import (
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the grpc package it is being compiled against.
-// Requires gRPC-Go v1.32.0 or later.
 const _ = grpc.SupportPackageIsVersion7
 
-// ChatClient is the client API for Chat service.
+// AuthClient is the client API for Auth service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
-type ChatClient interface {
-	ConnectChat(ctx context.Context, in *Room, opts ...grpc.CallOption) (*Client, error)
-	GetMessages(ctx context.Context, in *Client, opts ...grpc.CallOption) (Chat_GetMessagesClient, error)
-	SendMessage(ctx context.Context, in *MessageRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+type AuthClient interface {
+	Authenticate(ctx context.Context, in *AuthRequest, opts ...grpc.CallOption) (*Client, error)
 }
 
-type chatClient struct {
+type authClient struct {
 	cc grpc.ClientConnInterface
 }
 
-func NewChatClient(cc grpc.ClientConnInterface) ChatClient {
-	return &chatClient{cc}
+func NewAuthClient(cc grpc.ClientConnInterface) AuthClient {
+	return &authClient{cc}
 }
 
-func (c *chatClient) ConnectChat(ctx context.Context, in *Room, opts ...grpc.CallOption) (*Client, error) {
+func (c *authClient) Authenticate(ctx context.Context, in *AuthRequest, opts ...grpc.CallOption) (*Client, error) {
 	out := new(Client)
-	err := c.cc.Invoke(ctx, "/pb.Chat/ConnectChat", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/pb.Auth/Authenticate", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *chatClient) GetMessages(ctx context.Context, in *Client, opts ...grpc.CallOption) (Chat_GetMessagesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Chat_ServiceDesc.Streams[0], "/pb.Chat/GetMessages", opts...)
-	if err != nil {
+// AuthServer is the server API for Auth service.
+// All implementations must embed UnimplementedAuthServer
+// for forward compatibility
+type AuthServer interface {
+	Authenticate(context.Context, *AuthRequest) (*Client, error)
+	mustEmbedUnimplementedAuthServer()
+}
+
+// UnimplementedAuthServer must be embedded to have forward compatible implementations.
+type UnimplementedAuthServer struct {
+}
+
+func (UnimplementedAuthServer) Authenticate(context.Context, *AuthRequest) (*Client, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Authenticate not implemented")
+}
+func (UnimplementedAuthServer) mustEmbedUnimplementedAuthServer() {}
+
+// UnsafeAuthServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AuthServer will
+// result in compilation errors.
+type UnsafeAuthServer interface {
+	mustEmbedUnimplementedAuthServer()
+}
+
+func RegisterAuthServer(s *grpc.Server, srv AuthServer) {
+	s.RegisterService(&_Auth_serviceDesc, srv)
+}
+
+func _Auth_Authenticate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &chatGetMessagesClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(AuthServer).Authenticate(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Auth/Authenticate",
 	}
-	return x, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).Authenticate(ctx, req.(*AuthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type Chat_GetMessagesClient interface {
-	Recv() (*MessageResponse, error)
-	grpc.ClientStream
+var _Auth_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.Auth",
+	HandlerType: (*AuthServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Authenticate",
+			Handler:    _Auth_Authenticate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "services.proto",
 }
 
-type chatGetMessagesClient struct {
-	grpc.ClientStream
+// RoomClient is the client API for Room service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RoomClient interface {
+	GetRooms(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*RoomResponse, error)
+	AddBan(ctx context.Context, in *Ban, opts ...grpc.CallOption) (*empty.Empty, error)
+	GetBans(ctx context.Context, in *RoomRequest, opts ...grpc.CallOption) (*BanResponse, error)
 }
 
-func (x *chatGetMessagesClient) Recv() (*MessageResponse, error) {
-	m := new(MessageResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+type roomClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRoomClient(cc grpc.ClientConnInterface) RoomClient {
+	return &roomClient{cc}
+}
+
+func (c *roomClient) GetRooms(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*RoomResponse, error) {
+	out := new(RoomResponse)
+	err := c.cc.Invoke(ctx, "/pb.Room/GetRooms", in, out, opts...)
+	if err != nil {
 		return nil, err
 	}
-	return m, nil
+	return out, nil
 }
 
-func (c *chatClient) SendMessage(ctx context.Context, in *MessageRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+func (c *roomClient) AddBan(ctx context.Context, in *Ban, opts ...grpc.CallOption) (*empty.Empty, error) {
 	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/pb.Chat/SendMessage", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/pb.Room/AddBan", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// ChatServer is the server API for Chat service.
-// All implementations must embed UnimplementedChatServer
+func (c *roomClient) GetBans(ctx context.Context, in *RoomRequest, opts ...grpc.CallOption) (*BanResponse, error) {
+	out := new(BanResponse)
+	err := c.cc.Invoke(ctx, "/pb.Room/GetBans", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RoomServer is the server API for Room service.
+// All implementations must embed UnimplementedRoomServer
 // for forward compatibility
-type ChatServer interface {
-	ConnectChat(context.Context, *Room) (*Client, error)
-	GetMessages(*Client, Chat_GetMessagesServer) error
-	SendMessage(context.Context, *MessageRequest) (*empty.Empty, error)
-	mustEmbedUnimplementedChatServer()
+type RoomServer interface {
+	GetRooms(context.Context, *empty.Empty) (*RoomResponse, error)
+	AddBan(context.Context, *Ban) (*empty.Empty, error)
+	GetBans(context.Context, *RoomRequest) (*BanResponse, error)
+	mustEmbedUnimplementedRoomServer()
 }
 
-// UnimplementedChatServer must be embedded to have forward compatible implementations.
-type UnimplementedChatServer struct {
+// UnimplementedRoomServer must be embedded to have forward compatible implementations.
+type UnimplementedRoomServer struct {
 }
 
-func (UnimplementedChatServer) ConnectChat(context.Context, *Room) (*Client, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ConnectChat not implemented")
+func (UnimplementedRoomServer) GetRooms(context.Context, *empty.Empty) (*RoomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRooms not implemented")
 }
-func (UnimplementedChatServer) GetMessages(*Client, Chat_GetMessagesServer) error {
-	return status.Errorf(codes.Unimplemented, "method GetMessages not implemented")
+func (UnimplementedRoomServer) AddBan(context.Context, *Ban) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddBan not implemented")
 }
-func (UnimplementedChatServer) SendMessage(context.Context, *MessageRequest) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SendMessage not implemented")
+func (UnimplementedRoomServer) GetBans(context.Context, *RoomRequest) (*BanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBans not implemented")
 }
-func (UnimplementedChatServer) mustEmbedUnimplementedChatServer() {}
+func (UnimplementedRoomServer) mustEmbedUnimplementedRoomServer() {}
 
-// UnsafeChatServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to ChatServer will
+// UnsafeRoomServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RoomServer will
 // result in compilation errors.
-type UnsafeChatServer interface {
-	mustEmbedUnimplementedChatServer()
+type UnsafeRoomServer interface {
+	mustEmbedUnimplementedRoomServer()
 }
 
-func RegisterChatServer(s grpc.ServiceRegistrar, srv ChatServer) {
-	s.RegisterService(&Chat_ServiceDesc, srv)
+func RegisterRoomServer(s *grpc.Server, srv RoomServer) {
+	s.RegisterService(&_Room_serviceDesc, srv)
 }
 
-func _Chat_ConnectChat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Room)
+func _Room_GetRooms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChatServer).ConnectChat(ctx, in)
+		return srv.(RoomServer).GetRooms(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.Chat/ConnectChat",
+		FullMethod: "/pb.Room/GetRooms",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChatServer).ConnectChat(ctx, req.(*Room))
+		return srv.(RoomServer).GetRooms(ctx, req.(*empty.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Chat_GetMessages_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(Client)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _Room_AddBan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Ban)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(ChatServer).GetMessages(m, &chatGetMessagesServer{stream})
-}
-
-type Chat_GetMessagesServer interface {
-	Send(*MessageResponse) error
-	grpc.ServerStream
-}
-
-type chatGetMessagesServer struct {
-	grpc.ServerStream
-}
-
-func (x *chatGetMessagesServer) Send(m *MessageResponse) error {
-	return x.ServerStream.SendMsg(m)
+	if interceptor == nil {
+		return srv.(RoomServer).AddBan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Room/AddBan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServer).AddBan(ctx, req.(*Ban))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Chat_SendMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MessageRequest)
+func _Room_GetBans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoomRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChatServer).SendMessage(ctx, in)
+		return srv.(RoomServer).GetBans(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.Chat/SendMessage",
+		FullMethod: "/pb.Room/GetBans",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChatServer).SendMessage(ctx, req.(*MessageRequest))
+		return srv.(RoomServer).GetBans(ctx, req.(*RoomRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-// Chat_ServiceDesc is the grpc.ServiceDesc for Chat service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var Chat_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "pb.Chat",
-	HandlerType: (*ChatServer)(nil),
+var _Room_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.Room",
+	HandlerType: (*RoomServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "ConnectChat",
-			Handler:    _Chat_ConnectChat_Handler,
+			MethodName: "GetRooms",
+			Handler:    _Room_GetRooms_Handler,
 		},
 		{
-			MethodName: "SendMessage",
-			Handler:    _Chat_SendMessage_Handler,
+			MethodName: "AddBan",
+			Handler:    _Room_AddBan_Handler,
 		},
-	},
-	Streams: []grpc.StreamDesc{
 		{
-			StreamName:    "GetMessages",
-			Handler:       _Chat_GetMessages_Handler,
-			ServerStreams: true,
+			MethodName: "GetBans",
+			Handler:    _Room_GetBans_Handler,
 		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "services.proto",
 }
 
-// ImageClient is the client API for Image service.
+// ChatClient is the client API for Chat service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
-type ImageClient interface {
-	GetImage(ctx context.Context, in *Room, opts ...grpc.CallOption) (Image_GetImageClient, error)
-	GetWord(ctx context.Context, in *Room, opts ...grpc.CallOption) (Image_GetWordClient, error)
-	GetCountdown(ctx context.Context, in *Room, opts ...grpc.CallOption) (Image_GetCountdownClient, error)
+type ChatClient interface {
+	// Play is a player's whole session in one stream, see PlayRequest and
+	// PlayEvent. It replaces GetMessages, SendMessage, GetPresence and
+	// Image.GetImageAndWords, which are kept for the HTTP gateway and older
+	// clients.
+	Play(ctx context.Context, opts ...grpc.CallOption) (Chat_PlayClient, error)
+	GetMessages(ctx context.Context, in *MessageStreamRequest, opts ...grpc.CallOption) (Chat_GetMessagesClient, error)
+	SendMessage(ctx context.Context, in *MessageRequest, opts ...grpc.CallOption) (*MatchWordResponse, error)
+	ListPlayers(ctx context.Context, in *RoomRequest, opts ...grpc.CallOption) (*PlayerListResponse, error)
+	GetPresence(ctx context.Context, in *Client, opts ...grpc.CallOption) (Chat_GetPresenceClient, error)
+	// Moderation RPCs can only be called by the room owner or an admin.
+	MutePlayer(ctx context.Context, in *ModerationRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	KickPlayer(ctx context.Context, in *ModerationRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	BanPlayer(ctx context.Context, in *ModerationRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 }
 
-type imageClient struct {
+type chatClient struct {
 	cc grpc.ClientConnInterface
 }
 
-func NewImageClient(cc grpc.ClientConnInterface) ImageClient {
-	return &imageClient{cc}
+func NewChatClient(cc grpc.ClientConnInterface) ChatClient {
+	return &chatClient{cc}
 }
 
-func (c *imageClient) GetImage(ctx context.Context, in *Room, opts ...grpc.CallOption) (Image_GetImageClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Image_ServiceDesc.Streams[0], "/pb.Image/GetImage", opts...)
+func (c *chatClient) Play(ctx context.Context, opts ...grpc.CallOption) (Chat_PlayClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Chat_serviceDesc.Streams[0], "/pb.Chat/Play", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &imageGetImageClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
+	x := &chatPlayClient{stream}
 	return x, nil
 }
 
-type Image_GetImageClient interface {
-	Recv() (*ImageResponse, error)
+type Chat_PlayClient interface {
+	Send(*PlayRequest) error
+	Recv() (*PlayEvent, error)
 	grpc.ClientStream
 }
 
-type imageGetImageClient struct {
+type chatPlayClient struct {
 	grpc.ClientStream
 }
 
-func (x *imageGetImageClient) Recv() (*ImageResponse, error) {
-	m := new(ImageResponse)
+func (x *chatPlayClient) Send(m *PlayRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *chatPlayClient) Recv() (*PlayEvent, error) {
+	m := new(PlayEvent)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *imageClient) GetWord(ctx context.Context, in *Room, opts ...grpc.CallOption) (Image_GetWordClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Image_ServiceDesc.Streams[1], "/pb.Image/GetWord", opts...)
+func (c *chatClient) GetMessages(ctx context.Context, in *MessageStreamRequest, opts ...grpc.CallOption) (Chat_GetMessagesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Chat_serviceDesc.Streams[1], "/pb.Chat/GetMessages", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &imageGetWordClient{stream}
+	x := &chatGetMessagesClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -265,29 +325,47 @@ func (c *imageClient) GetWord(ctx context.Context, in *Room, opts ...grpc.CallOp
 	return x, nil
 }
 
-type Image_GetWordClient interface {
-	Recv() (*WordResponse, error)
+type Chat_GetMessagesClient interface {
+	Recv() (*MessageResponse, error)
 	grpc.ClientStream
 }
 
-type imageGetWordClient struct {
+type chatGetMessagesClient struct {
 	grpc.ClientStream
 }
 
-func (x *imageGetWordClient) Recv() (*WordResponse, error) {
-	m := new(WordResponse)
+func (x *chatGetMessagesClient) Recv() (*MessageResponse, error) {
+	m := new(MessageResponse)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *imageClient) GetCountdown(ctx context.Context, in *Room, opts ...grpc.CallOption) (Image_GetCountdownClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Image_ServiceDesc.Streams[2], "/pb.Image/GetCountdown", opts...)
+func (c *chatClient) SendMessage(ctx context.Context, in *MessageRequest, opts ...grpc.CallOption) (*MatchWordResponse, error) {
+	out := new(MatchWordResponse)
+	err := c.cc.Invoke(ctx, "/pb.Chat/SendMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) ListPlayers(ctx context.Context, in *RoomRequest, opts ...grpc.CallOption) (*PlayerListResponse, error) {
+	out := new(PlayerListResponse)
+	err := c.cc.Invoke(ctx, "/pb.Chat/ListPlayers", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &imageGetCountdownClient{stream}
+	return out, nil
+}
+
+func (c *chatClient) GetPresence(ctx context.Context, in *Client, opts ...grpc.CallOption) (Chat_GetPresenceClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Chat_serviceDesc.Streams[2], "/pb.Chat/GetPresence", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chatGetPresenceClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -297,143 +375,419 @@ func (c *imageClient) GetCountdown(ctx context.Context, in *Room, opts ...grpc.C
 	return x, nil
 }
 
-type Image_GetCountdownClient interface {
-	Recv() (*CountdownResponse, error)
+type Chat_GetPresenceClient interface {
+	Recv() (*PlayerListResponse, error)
 	grpc.ClientStream
 }
 
-type imageGetCountdownClient struct {
+type chatGetPresenceClient struct {
 	grpc.ClientStream
 }
 
-func (x *imageGetCountdownClient) Recv() (*CountdownResponse, error) {
-	m := new(CountdownResponse)
+func (x *chatGetPresenceClient) Recv() (*PlayerListResponse, error) {
+	m := new(PlayerListResponse)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-// ImageServer is the server API for Image service.
-// All implementations must embed UnimplementedImageServer
+func (c *chatClient) MutePlayer(ctx context.Context, in *ModerationRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/pb.Chat/MutePlayer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) KickPlayer(ctx context.Context, in *ModerationRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/pb.Chat/KickPlayer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) BanPlayer(ctx context.Context, in *ModerationRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/pb.Chat/BanPlayer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ChatServer is the server API for Chat service.
+// All implementations must embed UnimplementedChatServer
 // for forward compatibility
-type ImageServer interface {
-	GetImage(*Room, Image_GetImageServer) error
-	GetWord(*Room, Image_GetWordServer) error
-	GetCountdown(*Room, Image_GetCountdownServer) error
-	mustEmbedUnimplementedImageServer()
+type ChatServer interface {
+	// Play is a player's whole session in one stream, see PlayRequest and
+	// PlayEvent. It replaces GetMessages, SendMessage, GetPresence and
+	// Image.GetImageAndWords, which are kept for the HTTP gateway and older
+	// clients.
+	Play(Chat_PlayServer) error
+	GetMessages(*MessageStreamRequest, Chat_GetMessagesServer) error
+	SendMessage(context.Context, *MessageRequest) (*MatchWordResponse, error)
+	ListPlayers(context.Context, *RoomRequest) (*PlayerListResponse, error)
+	GetPresence(*Client, Chat_GetPresenceServer) error
+	// Moderation RPCs can only be called by the room owner or an admin.
+	MutePlayer(context.Context, *ModerationRequest) (*empty.Empty, error)
+	KickPlayer(context.Context, *ModerationRequest) (*empty.Empty, error)
+	BanPlayer(context.Context, *ModerationRequest) (*empty.Empty, error)
+	mustEmbedUnimplementedChatServer()
 }
 
-// UnimplementedImageServer must be embedded to have forward compatible implementations.
-type UnimplementedImageServer struct {
+// UnimplementedChatServer must be embedded to have forward compatible implementations.
+type UnimplementedChatServer struct {
 }
 
-func (UnimplementedImageServer) GetImage(*Room, Image_GetImageServer) error {
-	return status.Errorf(codes.Unimplemented, "method GetImage not implemented")
+func (UnimplementedChatServer) Play(Chat_PlayServer) error {
+	return status.Errorf(codes.Unimplemented, "method Play not implemented")
 }
-func (UnimplementedImageServer) GetWord(*Room, Image_GetWordServer) error {
-	return status.Errorf(codes.Unimplemented, "method GetWord not implemented")
+func (UnimplementedChatServer) GetMessages(*MessageStreamRequest, Chat_GetMessagesServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetMessages not implemented")
 }
-func (UnimplementedImageServer) GetCountdown(*Room, Image_GetCountdownServer) error {
-	return status.Errorf(codes.Unimplemented, "method GetCountdown not implemented")
+func (UnimplementedChatServer) SendMessage(context.Context, *MessageRequest) (*MatchWordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendMessage not implemented")
 }
-func (UnimplementedImageServer) mustEmbedUnimplementedImageServer() {}
+func (UnimplementedChatServer) ListPlayers(context.Context, *RoomRequest) (*PlayerListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPlayers not implemented")
+}
+func (UnimplementedChatServer) GetPresence(*Client, Chat_GetPresenceServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetPresence not implemented")
+}
+func (UnimplementedChatServer) MutePlayer(context.Context, *ModerationRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MutePlayer not implemented")
+}
+func (UnimplementedChatServer) KickPlayer(context.Context, *ModerationRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KickPlayer not implemented")
+}
+func (UnimplementedChatServer) BanPlayer(context.Context, *ModerationRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BanPlayer not implemented")
+}
+func (UnimplementedChatServer) mustEmbedUnimplementedChatServer() {}
 
-// UnsafeImageServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to ImageServer will
+// UnsafeChatServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ChatServer will
 // result in compilation errors.
-type UnsafeImageServer interface {
-	mustEmbedUnimplementedImageServer()
+type UnsafeChatServer interface {
+	mustEmbedUnimplementedChatServer()
 }
 
-func RegisterImageServer(s grpc.ServiceRegistrar, srv ImageServer) {
-	s.RegisterService(&Image_ServiceDesc, srv)
+func RegisterChatServer(s *grpc.Server, srv ChatServer) {
+	s.RegisterService(&_Chat_serviceDesc, srv)
 }
 
-func _Image_GetImage_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(Room)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(ImageServer).GetImage(m, &imageGetImageServer{stream})
+func _Chat_Play_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ChatServer).Play(&chatPlayServer{stream})
 }
 
-type Image_GetImageServer interface {
-	Send(*ImageResponse) error
+type Chat_PlayServer interface {
+	Send(*PlayEvent) error
+	Recv() (*PlayRequest, error)
 	grpc.ServerStream
 }
 
-type imageGetImageServer struct {
+type chatPlayServer struct {
 	grpc.ServerStream
 }
 
-func (x *imageGetImageServer) Send(m *ImageResponse) error {
+func (x *chatPlayServer) Send(m *PlayEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Image_GetWord_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(Room)
+func (x *chatPlayServer) Recv() (*PlayRequest, error) {
+	m := new(PlayRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Chat_GetMessages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MessageStreamRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(ImageServer).GetWord(m, &imageGetWordServer{stream})
+	return srv.(ChatServer).GetMessages(m, &chatGetMessagesServer{stream})
 }
 
-type Image_GetWordServer interface {
-	Send(*WordResponse) error
+type Chat_GetMessagesServer interface {
+	Send(*MessageResponse) error
 	grpc.ServerStream
 }
 
-type imageGetWordServer struct {
+type chatGetMessagesServer struct {
 	grpc.ServerStream
 }
 
-func (x *imageGetWordServer) Send(m *WordResponse) error {
+func (x *chatGetMessagesServer) Send(m *MessageResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Image_GetCountdown_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(Room)
+func _Chat_SendMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).SendMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Chat/SendMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).SendMessage(ctx, req.(*MessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_ListPlayers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).ListPlayers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Chat/ListPlayers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).ListPlayers(ctx, req.(*RoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_GetPresence_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Client)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(ImageServer).GetCountdown(m, &imageGetCountdownServer{stream})
+	return srv.(ChatServer).GetPresence(m, &chatGetPresenceServer{stream})
 }
 
-type Image_GetCountdownServer interface {
-	Send(*CountdownResponse) error
+type Chat_GetPresenceServer interface {
+	Send(*PlayerListResponse) error
 	grpc.ServerStream
 }
 
-type imageGetCountdownServer struct {
+type chatGetPresenceServer struct {
 	grpc.ServerStream
 }
 
-func (x *imageGetCountdownServer) Send(m *CountdownResponse) error {
+func (x *chatGetPresenceServer) Send(m *PlayerListResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-// Image_ServiceDesc is the grpc.ServiceDesc for Image service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var Image_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "pb.Image",
-	HandlerType: (*ImageServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+func _Chat_MutePlayer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).MutePlayer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Chat/MutePlayer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).MutePlayer(ctx, req.(*ModerationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_KickPlayer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).KickPlayer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Chat/KickPlayer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).KickPlayer(ctx, req.(*ModerationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_BanPlayer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).BanPlayer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Chat/BanPlayer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).BanPlayer(ctx, req.(*ModerationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Chat_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.Chat",
+	HandlerType: (*ChatServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SendMessage",
+			Handler:    _Chat_SendMessage_Handler,
+		},
+		{
+			MethodName: "ListPlayers",
+			Handler:    _Chat_ListPlayers_Handler,
+		},
+		{
+			MethodName: "MutePlayer",
+			Handler:    _Chat_MutePlayer_Handler,
+		},
+		{
+			MethodName: "KickPlayer",
+			Handler:    _Chat_KickPlayer_Handler,
+		},
+		{
+			MethodName: "BanPlayer",
+			Handler:    _Chat_BanPlayer_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
-			StreamName:    "GetImage",
-			Handler:       _Image_GetImage_Handler,
+			StreamName:    "Play",
+			Handler:       _Chat_Play_Handler,
 			ServerStreams: true,
+			ClientStreams: true,
 		},
 		{
-			StreamName:    "GetWord",
-			Handler:       _Image_GetWord_Handler,
+			StreamName:    "GetMessages",
+			Handler:       _Chat_GetMessages_Handler,
 			ServerStreams: true,
 		},
 		{
-			StreamName:    "GetCountdown",
-			Handler:       _Image_GetCountdown_Handler,
+			StreamName:    "GetPresence",
+			Handler:       _Chat_GetPresence_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "services.proto",
+}
+
+// ImageClient is the client API for Image service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ImageClient interface {
+	GetImageAndWords(ctx context.Context, in *Client, opts ...grpc.CallOption) (Image_GetImageAndWordsClient, error)
+}
+
+type imageClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewImageClient(cc grpc.ClientConnInterface) ImageClient {
+	return &imageClient{cc}
+}
+
+func (c *imageClient) GetImageAndWords(ctx context.Context, in *Client, opts ...grpc.CallOption) (Image_GetImageAndWordsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Image_serviceDesc.Streams[0], "/pb.Image/GetImageAndWords", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &imageGetImageAndWordsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Image_GetImageAndWordsClient interface {
+	Recv() (*ImageWordResponse, error)
+	grpc.ClientStream
+}
+
+type imageGetImageAndWordsClient struct {
+	grpc.ClientStream
+}
+
+func (x *imageGetImageAndWordsClient) Recv() (*ImageWordResponse, error) {
+	m := new(ImageWordResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ImageServer is the server API for Image service.
+// All implementations must embed UnimplementedImageServer
+// for forward compatibility
+type ImageServer interface {
+	GetImageAndWords(*Client, Image_GetImageAndWordsServer) error
+	mustEmbedUnimplementedImageServer()
+}
+
+// UnimplementedImageServer must be embedded to have forward compatible implementations.
+type UnimplementedImageServer struct {
+}
+
+func (UnimplementedImageServer) GetImageAndWords(*Client, Image_GetImageAndWordsServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetImageAndWords not implemented")
+}
+func (UnimplementedImageServer) mustEmbedUnimplementedImageServer() {}
+
+// UnsafeImageServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ImageServer will
+// result in compilation errors.
+type UnsafeImageServer interface {
+	mustEmbedUnimplementedImageServer()
+}
+
+func RegisterImageServer(s *grpc.Server, srv ImageServer) {
+	s.RegisterService(&_Image_serviceDesc, srv)
+}
+
+func _Image_GetImageAndWords_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Client)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ImageServer).GetImageAndWords(m, &imageGetImageAndWordsServer{stream})
+}
+
+type Image_GetImageAndWordsServer interface {
+	Send(*ImageWordResponse) error
+	grpc.ServerStream
+}
+
+type imageGetImageAndWordsServer struct {
+	grpc.ServerStream
+}
+
+func (x *imageGetImageAndWordsServer) Send(m *ImageWordResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Image_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.Image",
+	HandlerType: (*ImageServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetImageAndWords",
+			Handler:       _Image_GetImageAndWords_Handler,
 			ServerStreams: true,
 		},
 	},
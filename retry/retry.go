@@ -0,0 +1,54 @@
+package retry
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"time"
+)
+
+const (
+	baseDelay = 500 * time.Millisecond
+	maxDelay  = 30 * time.Second
+)
+
+// Backoff returns exponentially growing delays with jitter, capped at maxDelay.
+type Backoff struct {
+	attempt int
+}
+
+func (b *Backoff) Next() time.Duration {
+	d := baseDelay << uint(b.attempt)
+	if d > maxDelay || d <= 0 {
+		d = maxDelay
+	} else {
+		b.attempt++
+	}
+
+	// Spread retries from many callers over [d/2, d).
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)))
+}
+
+func (b *Backoff) Reset() {
+	b.attempt = 0
+}
+
+// Do calls f until it returns nil or ctx is done, backing off between calls.
+// It returns ctx.Err() if ctx ends first.
+func Do(ctx context.Context, name string, f func() error) error {
+	var b Backoff
+	for {
+		err := f()
+		if err == nil {
+			return nil
+		}
+
+		d := b.Next()
+		log.Printf("%s failed, retrying in %s: %v", name, d.Round(time.Millisecond), err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+		}
+	}
+}
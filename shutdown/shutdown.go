@@ -0,0 +1,114 @@
+package shutdown
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Serve runs srv on lis until it fails or the process gets SIGINT or SIGTERM.
+// On a signal it calls drain, which should end open streams, stops srv
+// gracefully, forcing it once timeout passes, and then closes closers in order.
+func Serve(name string, srv *grpc.Server, lis net.Listener, timeout time.Duration, drain func(), closers ...io.Closer) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Serve(lis)
+	}()
+
+	if stopped, err := wait(name, errs); stopped {
+		return err
+	}
+
+	if drain != nil {
+		drain()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		log.Printf("%s did not stop within %s, closing remaining connections", name, timeout)
+		srv.Stop()
+	}
+
+	closeAll(name, closers)
+	return nil
+}
+
+// ServeHTTP is Serve for an http.Server. Open requests, including event
+// streams, get until timeout to finish before their connections are closed.
+func ServeHTTP(name string, srv *http.Server, lis net.Listener, timeout time.Duration, closers ...io.Closer) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Serve(lis)
+	}()
+
+	if stopped, err := wait(name, errs); stopped {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("%s did not stop within %s, closing remaining connections", name, timeout)
+		srv.Close()
+	}
+
+	closeAll(name, closers)
+	return nil
+}
+
+// ServeCloser is Serve for a server with nothing to drain, which serve runs
+// until it fails or srv is closed on a signal. closers are closed after it.
+func ServeCloser(name string, serve func() error, srv io.Closer, closers ...io.Closer) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- serve()
+	}()
+
+	if stopped, err := wait(name, errs); stopped {
+		return err
+	}
+
+	closeAll(name, append([]io.Closer{srv}, closers...))
+	return nil
+}
+
+// wait blocks until serving stops, returning true and its error, or the
+// process gets SIGINT or SIGTERM.
+func wait(name string, errs <-chan error) (bool, error) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case err := <-errs:
+		return true, err
+	case sig := <-signals:
+		log.Printf("Received %s, shutting down %s", sig, name)
+		return false, nil
+	}
+}
+
+func closeAll(name string, closers []io.Closer) {
+	for _, c := range closers {
+		if err := c.Close(); err != nil {
+			log.Printf("Error closing %T: %v", c, err)
+		}
+	}
+
+	log.Printf("%s stopped", name)
+}
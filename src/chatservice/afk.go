@@ -0,0 +1,90 @@
+package chatservice
+
+import (
+	"log"
+
+	"github.com/richardjaytea/infipic/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errIdle = status.Error(codes.FailedPrecondition, "disconnected for being idle")
+
+var (
+	// userActive is true for players who guessed, chatted or sent an active
+	// heartbeat this round.
+	userActive map[string]bool
+	// userIdleRounds counts the rounds in a row each player has been idle.
+	userIdleRounds map[string]int
+)
+
+// markActive records that the player did something this round, and tells the
+// room if they were AFK.
+func (s *Server) markActive(roomKey, id string) {
+	s.lock.Lock()
+	wasAFK := isAFK(id)
+	userActive[id] = true
+	userIdleRounds[id] = 0
+	s.lock.Unlock()
+
+	if wasAFK {
+		log.Printf("Back From AFK: %s %s", roomKey, id)
+		s.broadcastPresence(roomKey)
+	}
+}
+
+// countIdleRounds is called as a round ends, and counts it as idle for every
+// player in the room who did nothing. It returns the players idle long enough
+// to be disconnected. It must be called with s.lock held.
+func (s *Server) countIdleRounds(roomKey string) []string {
+	limit := settings().AFKDisconnectRounds
+
+	var idle []string
+	for id := range s.roomChatStreams[roomKey] {
+		if userActive[id] {
+			userIdleRounds[id] = 0
+		} else {
+			userIdleRounds[id]++
+		}
+		userActive[id] = false
+
+		if limit > 0 && userIdleRounds[id] >= limit {
+			idle = append(idle, id)
+		}
+	}
+
+	return idle
+}
+
+// isAFK must be called with s.lock held.
+func isAFK(id string) bool {
+	rounds := settings().AFKRounds
+	return rounds > 0 && userIdleRounds[id] >= rounds
+}
+
+// everyoneGuessed reports whether every player still playing has guessed all
+// the words, leaving out AFK and disconnected players. Players on other
+// replicas count as of their last roster. It must be called with s.lock held.
+func (s *Server) everyoneGuessed(roomKey string) bool {
+	playing := 0
+	for id, stream := range s.roomChatStreams[roomKey] {
+		if _, disconnected := stream.(*suspended); disconnected || isAFK(id) {
+			continue
+		}
+		if playerStatus(roomKey, id) != pb.PlayerStatus_GUESSED_ALL {
+			return false
+		}
+		playing++
+	}
+	for _, p := range s.remotePlayers(roomKey) {
+		if p.Disconnected || p.Afk {
+			continue
+		}
+		if p.Status != pb.PlayerStatus_GUESSED_ALL {
+			return false
+		}
+		playing++
+	}
+
+	return playing > 0
+}
@@ -0,0 +1,121 @@
+package chatservice
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/richardjaytea/infipic/config"
+	"github.com/richardjaytea/infipic/healthcheck"
+	"github.com/richardjaytea/infipic/pb"
+)
+
+const benchmarkSubscribers = 1000
+
+// fakeStream counts the messages sent on it, taking delay over each.
+type fakeStream struct {
+	received *sync.WaitGroup
+	delay    time.Duration
+	sends    int64
+}
+
+func (f *fakeStream) Send(*pb.MessageResponse) error {
+	if f.delay > 0 {
+		time.Sleep(f.delay)
+	}
+	atomic.AddInt64(&f.sends, 1)
+	if f.received != nil {
+		f.received.Done()
+	}
+	return nil
+}
+
+func (f *fakeStream) Context() context.Context {
+	return context.Background()
+}
+
+// BenchmarkBroadcast broadcasts to a room of 1,000 players, one of which may
+// take 5ms over every message, and waits for the others to get it.
+// broadcast-ns/op is how long the sender was held up. The unqueued cases send
+// on the broadcaster's goroutine as streams did before send queues.
+func BenchmarkBroadcast(b *testing.B) {
+	for _, policy := range []string{"", "drop", "disconnect"} {
+		for _, slow := range []bool{false, true} {
+			name := policy
+			if name == "" {
+				name = "unqueued"
+			}
+			if slow {
+				name += "/one_slow"
+			}
+
+			b.Run(name, func(b *testing.B) {
+				benchmarkBroadcast(b, policy, slow)
+			})
+		}
+	}
+}
+
+func benchmarkBroadcast(b *testing.B, policy string, slow bool) {
+	cfg := config.DefaultChat()
+	if policy != "" {
+		cfg.SlowConsumerPolicy = policy
+	}
+	Configure(cfg)
+
+	s := New(healthcheck.NewServer())
+	streams := messageStreamMap{}
+	s.roomChatStreams["room"] = streams
+
+	var received sync.WaitGroup
+	var slowStream *fakeStream
+	var subs []*subscriber
+	for i := 0; i < benchmarkSubscribers; i++ {
+		f := &fakeStream{received: &received}
+		if slow && i == 0 {
+			f = &fakeStream{delay: 5 * time.Millisecond}
+			slowStream = f
+		}
+
+		id := fmt.Sprintf("player%d", i)
+		if policy == "" {
+			streams[id] = f
+			continue
+		}
+		sub := newSubscriber(context.Background())
+		subs = append(subs, sub)
+		streams[id] = &messageSubscriber{subscriber: sub, stream: f}
+	}
+	defer func() {
+		for _, sub := range subs {
+			sub.close()
+		}
+	}()
+
+	fast := benchmarkSubscribers
+	if slow {
+		fast--
+	}
+	m := s.buildMessageResponse("room", "player", "apple")
+
+	var broadcasting time.Duration
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		received.Add(fast)
+		start := time.Now()
+		s.broadcastMessage("room", m)
+		broadcasting += time.Since(start)
+		received.Wait()
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(broadcasting.Nanoseconds())/float64(b.N), "broadcast-ns/op")
+
+	if slowStream != nil {
+		b.ReportMetric(float64(atomic.LoadInt64(&slowStream.sends))/float64(b.N), "slow-sends/op")
+	}
+}
@@ -0,0 +1,85 @@
+package chatservice
+
+import (
+	"github.com/richardjaytea/infipic/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// deliverMessage sends a player's chat message to the players on its channel.
+// Whispers are echoed back to the sender so they show up in their own chat.
+func (s *Server) deliverMessage(message *pb.MessageRequest) error {
+	s.lock.RLock()
+	name := userNames[message.Id]
+	team := userTeams[message.Id]
+	solved := playerStatus(message.RoomKey, message.Id) == pb.PlayerStatus_GUESSED_ALL
+	_, targetFound := s.roomChatStreams[message.RoomKey][message.TargetId]
+	targetFound = targetFound || s.remotePlayer(message.RoomKey, message.TargetId) != nil
+	s.lock.RUnlock()
+
+	var to *pb.Audience
+	switch message.Channel {
+	case pb.Channel_EVERYONE:
+	case pb.Channel_WHISPER:
+		if !targetFound {
+			return status.Errorf(codes.NotFound, "player %s is not in room %s", message.TargetId, message.RoomKey)
+		}
+		to = &pb.Audience{Ids: []string{message.TargetId, message.Id}}
+	case pb.Channel_TEAM:
+		if team == "" {
+			return status.Error(codes.FailedPrecondition, "you are not on a team")
+		}
+		to = &pb.Audience{Team: team}
+	case pb.Channel_SOLVERS:
+		if !solved {
+			return status.Error(codes.FailedPrecondition, "only players who have guessed every word can use this channel")
+		}
+		to = &pb.Audience{Solvers: true}
+	default:
+		return status.Errorf(codes.InvalidArgument, "unknown channel %v", message.Channel)
+	}
+
+	if err := s.checkMuted(message); err != nil {
+		return err
+	}
+
+	content, err := s.filterProfanity(message)
+	if err != nil {
+		return err
+	}
+
+	if message.Channel != pb.Channel_SOLVERS {
+		if content, to, err = s.filterLeaks(message, content, to); err != nil {
+			return err
+		}
+	}
+
+	response := s.buildMessageResponse(message.RoomKey, name, content)
+	response.Channel = message.Channel
+	s.shareMessage(message.RoomKey, response, to)
+	return nil
+}
+
+// includes reports whether the player is in the audience. It must be called
+// with s.lock held.
+func includes(roomKey string, a *pb.Audience, id string) bool {
+	if contains(a.Also, id) {
+		return true
+	}
+	if len(a.Ids) > 0 && !contains(a.Ids, id) {
+		return false
+	}
+	if a.Team != "" && userTeams[id] != a.Team {
+		return false
+	}
+	if a.Solvers && playerStatus(roomKey, id) != pb.PlayerStatus_GUESSED_ALL {
+		return false
+	}
+	for _, w := range a.Guessed {
+		if !contains(userWords[id], w) {
+			return false
+		}
+	}
+
+	return true
+}
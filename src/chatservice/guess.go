@@ -0,0 +1,141 @@
+package chatservice
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/richardjaytea/infipic/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var userGuesses map[string]*guessState
+
+type wrongGuess struct {
+	word string
+	at   time.Time
+}
+
+// guessState tracks a player's guesses in the current round. flagged and
+// cooldownUntil carry over between rounds.
+type guessState struct {
+	attempts      int
+	wrongStreak   int
+	recent        []wrongGuess
+	cooldownUntil time.Time
+	flagged       bool
+}
+
+// checkGuess refuses a guess if the player used up their guesses for the round
+// or is on cooldown. It must be called with s.lock held.
+func checkGuess(ctx context.Context, id string, now time.Time) error {
+	g, ok := userGuesses[id]
+	if !ok {
+		return nil
+	}
+
+	if wait := g.cooldownUntil.Sub(now); wait > 0 {
+		seconds := int(math.Ceil(wait.Seconds()))
+		grpc.SetTrailer(ctx, metadata.Pairs(retryAfterKey, strconv.Itoa(seconds)))
+		return status.Errorf(codes.ResourceExhausted, "too many wrong guesses, retry after %ds", seconds)
+	}
+	if g.attempts >= settings().GuessLimitPerRound {
+		return status.Error(codes.ResourceExhausted, "no guesses left this round")
+	}
+
+	return nil
+}
+
+// recordGuess counts a guess and puts the player on cooldown after a streak of
+// wrong guesses or a burst of different ones, which looks like a dictionary
+// attack and flags them. It returns whether the guess flagged the player for
+// the first time, which is for reportFlagged. It must be called with s.lock
+// held.
+func recordGuess(id, word string, correct bool, now time.Time) bool {
+	g, ok := userGuesses[id]
+	if !ok {
+		g = &guessState{}
+		userGuesses[id] = g
+	}
+
+	g.attempts++
+	if correct {
+		g.wrongStreak = 0
+		return false
+	}
+	g.wrongStreak++
+
+	cfg := settings()
+	window := cfg.BruteForceWindow
+	recent := g.recent[:0]
+	for _, r := range g.recent {
+		if now.Sub(r.at) < window && r.word != word {
+			recent = append(recent, r)
+		}
+	}
+	g.recent = append(recent, wrongGuess{word, now})
+
+	first := false
+	if len(g.recent) >= cfg.BruteForceDistinctGuesses {
+		if !g.flagged {
+			log.Printf("Player Flagged: %s made %d different guesses in %s", id, len(g.recent), window)
+			first = true
+		}
+		g.flagged = true
+		g.recent = nil
+		g.wrongStreak = 0
+		g.cooldownUntil = now.Add(cfg.GuessCooldown)
+	} else if g.wrongStreak >= cfg.WrongGuessStreak {
+		g.wrongStreak = 0
+		g.cooldownUntil = now.Add(cfg.GuessCooldown)
+	}
+
+	return first
+}
+
+// isFlagged reports whether the player has been flagged for guessing like a
+// dictionary attack. It must be called with s.lock held.
+func isFlagged(id string) bool {
+	g, ok := userGuesses[id]
+	return ok && g.flagged
+}
+
+// reportFlagged tells the room owner, on whichever replica they are, that the
+// player has been flagged so they can act on it, and shows the flag in the
+// player list.
+func (s *Server) reportFlagged(roomKey, id string) {
+	s.lock.RLock()
+	owner := s.roomOwner(roomKey)
+	name := userNames[id]
+	s.lock.RUnlock()
+
+	if owner != "" && owner != id {
+		cfg := settings()
+		reason := fmt.Sprintf("made %d different wrong guesses within %s", cfg.BruteForceDistinctGuesses, cfg.BruteForceWindow)
+		notice := s.buildSystemMessage(roomKey, pb.MessageKind_MODERATION, fmt.Sprintf("%s was flagged: %s.", name, reason))
+		notice.Payload = &pb.MessageResponse_Moderation{Moderation: &pb.ModerationEvent{
+			PlayerId: id,
+			Name:     name,
+			Action:   pb.ModerationEvent_FLAGGED,
+			Reason:   reason,
+		}}
+		s.shareMessage(roomKey, notice, &pb.Audience{Ids: []string{owner}})
+	}
+	s.broadcastPresence(roomKey)
+}
+
+// resetGuesses starts a new round for the player. It must be called with
+// s.lock held.
+func resetGuesses(id string) {
+	if g, ok := userGuesses[id]; ok {
+		g.attempts = 0
+		g.wrongStreak = 0
+		g.recent = nil
+	}
+}
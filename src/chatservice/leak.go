@@ -0,0 +1,218 @@
+package chatservice
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/richardjaytea/infipic/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	leakMask    = "mask"
+	leakBlock   = "block"
+	leakSolvers = "solvers"
+)
+
+var (
+	userLeaks    map[string]int
+	wordSuffixes = []string{"s", "es", "ed", "ing", "er"}
+)
+
+type token struct {
+	start, end int
+	text       string
+}
+
+// filterLeaks checks a message from a player who has already guessed a word
+// for tokens that give away one of the round's words, and applies LEAK_POLICY.
+// It returns the content to send and who to send it to.
+func (s *Server) filterLeaks(message *pb.MessageRequest, content string, to *pb.Audience) (string, *pb.Audience, error) {
+	s.lock.Lock()
+	if len(userWords[message.Id]) == 0 {
+		s.lock.Unlock()
+		return content, to, nil
+	}
+
+	leaked := matchTokens(tokenize(content), roomWords[message.RoomKey], revealsWord)
+	if len(leaked) == 0 {
+		s.lock.Unlock()
+		return content, to, nil
+	}
+
+	userLeaks[message.Id]++
+	offences := userLeaks[message.Id]
+	s.lock.Unlock()
+
+	log.Printf("Leak Filtered: %s %s offence %d", message.RoomKey, message.Id, offences)
+
+	switch settings().LeakPolicy {
+	case leakBlock:
+		return "", nil, status.Error(codes.FailedPrecondition, "message would reveal an answer")
+	case leakSolvers:
+		var words []string
+		for _, t := range leaked {
+			words = append(words, t.word)
+		}
+		solvers := &pb.Audience{}
+		if to != nil {
+			solvers = proto.Clone(to).(*pb.Audience)
+		}
+		solvers.Guessed = words
+		solvers.Also = append(solvers.Also, message.Id)
+		return content, solvers, nil
+	default:
+		s.sendModerationNotice(message, pb.ModerationEvent_MASKED,
+			fmt.Sprintf("Part of your message was hidden because it revealed an answer (offence %d).", offences))
+		return maskTokens(content, leaked), to, nil
+	}
+}
+
+func (s *Server) sendModerationNotice(message *pb.MessageRequest, action pb.ModerationEvent_Action, reason string) {
+	s.lock.RLock()
+	name := userNames[message.Id]
+	s.lock.RUnlock()
+
+	notice := s.buildSystemMessage(message.RoomKey, pb.MessageKind_MODERATION, reason)
+	notice.Payload = &pb.MessageResponse_Moderation{Moderation: &pb.ModerationEvent{
+		PlayerId: message.Id,
+		Name:     name,
+		Action:   action,
+		Reason:   reason,
+	}}
+	s.sendToPlayer(message.RoomKey, message.Id, notice)
+}
+
+// tokenize splits content into runs of letters and digits. Runs of single
+// characters such as "d o g" or "d.o.g" are also joined into one token.
+func tokenize(content string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range content + " " {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && start < 0 {
+			start = i
+		} else if !isWord && start >= 0 {
+			tokens = append(tokens, token{start, i, strings.ToLower(content[start:i])})
+			start = -1
+		}
+	}
+
+	var joined []token
+	for i := 0; i < len(tokens); {
+		j := i
+		var b strings.Builder
+		for j < len(tokens) && len([]rune(tokens[j].text)) == 1 {
+			b.WriteString(tokens[j].text)
+			j++
+		}
+		if j-i > 1 {
+			joined = append(joined, token{tokens[i].start, tokens[j-1].end, b.String()})
+			i = j
+			continue
+		}
+		i++
+	}
+
+	return append(tokens, joined...)
+}
+
+type tokenMatch struct {
+	token
+	word string
+}
+
+// matchTokens returns the tokens for which match returns true against any of
+// the words, along with the word each one matched.
+func matchTokens(tokens []token, words []string, match func(t, w string) bool) []tokenMatch {
+	var matches []tokenMatch
+	for _, t := range tokens {
+		for _, w := range words {
+			if match(t.text, w) {
+				matches = append(matches, tokenMatch{t, w})
+				break
+			}
+		}
+	}
+
+	return matches
+}
+
+// isWordForm reports whether t is w or w with a common suffix.
+func isWordForm(t, w string) bool {
+	if t == w {
+		return true
+	}
+	for _, suffix := range wordSuffixes {
+		if t == w+suffix {
+			return true
+		}
+	}
+
+	return false
+}
+
+// revealsWord reports whether t is a form of w or, for longer words, a single
+// typo away from w.
+func revealsWord(t, w string) bool {
+	return isWordForm(t, w) || len([]rune(w)) >= 4 && editDistance(t, w) <= 1
+}
+
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func maskTokens(content string, matches []tokenMatch) string {
+	masked := []rune(content)
+	offsets := make(map[int]int)
+	n := 0
+	for i := range content {
+		offsets[i] = n
+		n++
+	}
+	offsets[len(content)] = n
+
+	for _, m := range matches {
+		for i := offsets[m.start]; i < offsets[m.end]; i++ {
+			if !unicode.IsSpace(masked[i]) {
+				masked[i] = '*'
+			}
+		}
+	}
+
+	return string(masked)
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
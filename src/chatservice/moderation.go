@@ -0,0 +1,401 @@
+package chatservice
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/richardjaytea/infipic/pb"
+	"github.com/richardjaytea/infipic/retry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	profanityMask   = "mask"
+	profanityReject = "reject"
+	adminTokenKey   = "x-admin-token"
+	// forwardedForKey is the metadata proxies such as the gateway pass the
+	// addresses a call came through in, each adding the one it came from.
+	forwardedForKey = "x-forwarded-for"
+	// banTimeout is how long to keep trying to save a ban on the address of a
+	// player banned on another replica.
+	banTimeout = time.Minute
+)
+
+var (
+	profanity  []string
+	mutedUntil map[string]time.Time
+	userKicks  map[string]chan error
+	roomOwners map[string]string
+	// userAddresses holds the address each player connected from, which bans
+	// are tied to as it does not change when they authenticate again.
+	userAddresses map[string]string
+)
+
+func (s *Server) MutePlayer(ctx context.Context, r *pb.ModerationRequest) (*empty.Empty, error) {
+	if r.MuteSeconds <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "muteSeconds must be positive")
+	}
+	if err := s.checkModerator(ctx, r); err != nil {
+		return nil, err
+	}
+
+	d := time.Duration(r.MuteSeconds) * time.Second
+	s.moderate(r, pb.ModerationEvent_MUTED)
+
+	s.broadcastModeration(r, pb.ModerationEvent_MUTED, fmt.Sprintf("has been muted for %s", d))
+	return &empty.Empty{}, nil
+}
+
+func (s *Server) KickPlayer(ctx context.Context, r *pb.ModerationRequest) (*empty.Empty, error) {
+	if err := s.checkModerator(ctx, r); err != nil {
+		return nil, err
+	}
+
+	s.broadcastModeration(r, pb.ModerationEvent_KICKED, "has been kicked")
+	s.moderate(r, pb.ModerationEvent_KICKED)
+	return &empty.Empty{}, nil
+}
+
+// BanPlayer kicks the target and saves a ban on their id and address in the
+// room service, so they can not join the room again, along with their name
+// for moderators. The address of a
+// player on another replica is saved by that replica.
+func (s *Server) BanPlayer(ctx context.Context, r *pb.ModerationRequest) (*empty.Empty, error) {
+	if err := s.checkModerator(ctx, r); err != nil {
+		return nil, err
+	}
+
+	s.lock.RLock()
+	name := s.playerName(r.RoomKey, r.TargetId)
+	address := userAddresses[r.TargetId]
+	s.lock.RUnlock()
+
+	if _, err := s.roomClient.AddBan(ctx, &pb.Ban{
+		RoomKey:  r.RoomKey,
+		PlayerId: r.TargetId,
+		Name:     name,
+		Reason:   r.Reason,
+		Address:  address,
+	}); err != nil {
+		return nil, err
+	}
+
+	s.broadcastModeration(r, pb.ModerationEvent_BANNED, "has been banned")
+	s.moderate(r, pb.ModerationEvent_BANNED)
+	return &empty.Empty{}, nil
+}
+
+// moderate mutes or kicks the target, on whichever replica they are on.
+func (s *Server) moderate(r *pb.ModerationRequest, action pb.ModerationEvent_Action) {
+	m := &pb.Moderation{
+		Action:      action,
+		TargetId:    r.TargetId,
+		Reason:      r.Reason,
+		MuteSeconds: r.MuteSeconds,
+	}
+
+	s.applyModeration(m)
+	s.publish(r.RoomKey, &pb.RoomEvent{Event: &pb.RoomEvent_Moderation{Moderation: m}})
+}
+
+// applyModeration mutes or kicks the target if they are on this replica.
+func (s *Server) applyModeration(m *pb.Moderation) {
+	s.lock.Lock()
+	if _, ok := userNames[m.TargetId]; !ok {
+		s.lock.Unlock()
+		return
+	}
+	if m.Action == pb.ModerationEvent_MUTED {
+		mutedUntil[m.TargetId] = time.Now().Add(time.Duration(m.MuteSeconds) * time.Second)
+	}
+	s.lock.Unlock()
+
+	switch m.Action {
+	case pb.ModerationEvent_KICKED:
+		s.kick(m.TargetId, status.Errorf(codes.PermissionDenied, "you were kicked from the room: %s", m.Reason))
+	case pb.ModerationEvent_BANNED:
+		s.kick(m.TargetId, status.Errorf(codes.PermissionDenied, "you were banned from the room: %s", m.Reason))
+	}
+}
+
+// checkModerator makes sure the target is in the room, on this or another
+// replica, and that the caller is either the room owner or has sent the admin
+// token.
+func (s *Server) checkModerator(ctx context.Context, r *pb.ModerationRequest) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	streams, ok := s.roomChatStreams[r.RoomKey]
+	if !ok {
+		return status.Errorf(codes.NotFound, "room %s does not exist", r.RoomKey)
+	}
+	if _, ok := streams[r.TargetId]; !ok && s.remotePlayer(r.RoomKey, r.TargetId) == nil {
+		return status.Errorf(codes.NotFound, "player %s is not in room %s", r.TargetId, r.RoomKey)
+	}
+	if r.TargetId == r.Id {
+		return status.Error(codes.InvalidArgument, "you can not moderate yourself")
+	}
+	if roomOwners[r.RoomKey] != r.Id && !isAdmin(ctx) {
+		return status.Error(codes.PermissionDenied, "only the room owner or an admin can moderate players")
+	}
+
+	return nil
+}
+
+func isAdmin(ctx context.Context) bool {
+	token := settings().AdminToken
+	if token == "" {
+		return false
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get(adminTokenKey) {
+		if subtle.ConstantTimeCompare([]byte(v), []byte(token)) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// banAddress saves a ban on the address of a player on this replica who was
+// banned on another, which could not know it. It must not block, as it is
+// called by receive.
+func (s *Server) banAddress(roomKey string, m *pb.Moderation) {
+	s.lock.RLock()
+	address := userAddresses[m.TargetId]
+	name := userNames[m.TargetId]
+	s.lock.RUnlock()
+	if address == "" {
+		return
+	}
+
+	b := &pb.Ban{RoomKey: roomKey, PlayerId: m.TargetId, Name: name, Reason: m.Reason, Address: address}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), banTimeout)
+		defer cancel()
+
+		err := retry.Do(ctx, "ban address", func() error {
+			_, err := s.roomClient.AddBan(ctx, b)
+			return err
+		})
+		if err != nil {
+			log.Printf("Failed to ban the address of %s in %s: %v", m.TargetId, roomKey, err)
+		}
+	}()
+}
+
+// checkBanned asks the room service whether the player's id or address is
+// banned. Anyone can authenticate again for a new id, so the address is what
+// keeps banned players out. Names are only saved for moderators, as anyone can
+// pick one. Players are refused while the bans can not be read.
+func (s *Server) checkBanned(ctx context.Context, m *pb.MessageStreamRequest, address string) error {
+	r, err := s.roomClient.GetBans(ctx, &pb.RoomRequest{RoomKey: m.RoomKey})
+	if err != nil {
+		log.Printf("Could not check bans for %s: %v", m.RoomKey, err)
+		return status.Error(codes.Unavailable, "could not check bans, try again later")
+	}
+
+	for _, b := range r.GetBans() {
+		if b.PlayerId == m.Id || (b.Address != "" && b.Address == address) {
+			return status.Errorf(codes.PermissionDenied, "you are banned from this room: %s", b.Reason)
+		}
+	}
+
+	return nil
+}
+
+// playerAddress returns the IP address of the player making the call in ctx,
+// or "" if it is not known. Calls from a proxy in TRUSTED_PROXIES, such as the
+// gateway, are from the last address in their X-Forwarded-For that is not a
+// trusted proxy too. So are in-process calls, which have no IP and come from
+// the gateway of the all-in-one binary. Other calls from this host are most
+// likely from a gateway not in TRUSTED_PROXIES, and its address would ban
+// every player behind it, so it is not used.
+func playerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	address := hostIP(p.Addr.String())
+	trusted := make(map[string]bool)
+	for _, proxy := range strings.Split(settings().TrustedProxies, ",") {
+		if ip := hostIP(strings.TrimSpace(proxy)); ip != "" {
+			trusted[ip] = true
+		}
+	}
+	if address != "" && !trusted[address] {
+		if net.ParseIP(address).IsLoopback() {
+			return ""
+		}
+		return address
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	forwarded := strings.Split(strings.Join(md.Get(forwardedForKey), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		if ip := hostIP(strings.TrimSpace(forwarded[i])); ip != "" && !trusted[ip] {
+			return ip
+		}
+	}
+
+	return ""
+}
+
+// hostIP returns the IP address in addr, a host:port or host, or "" if the
+// host is not an IP address.
+func hostIP(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return ""
+	}
+
+	return ip.String()
+}
+
+func (s *Server) kick(id string, err error) {
+	s.lock.RLock()
+	kick, ok := userKicks[id]
+	s.lock.RUnlock()
+
+	if ok {
+		select {
+		case kick <- err:
+		default:
+		}
+	}
+}
+
+// setOwner makes id the room owner if it has none, on any replica. It must be
+// called with s.lock held.
+func (s *Server) setOwner(roomKey, id string) {
+	if roomOwners[roomKey] != "" {
+		return
+	}
+	for _, p := range s.remotePlayers(roomKey) {
+		if p.Owner {
+			return
+		}
+	}
+
+	roomOwners[roomKey] = id
+}
+
+// roomOwner returns the id of the room owner, on this or another replica, or
+// "" if the room has none. It must be called with s.lock held.
+func (s *Server) roomOwner(roomKey string) string {
+	if id := roomOwners[roomKey]; id != "" {
+		return id
+	}
+	for _, p := range s.remotePlayers(roomKey) {
+		if p.Owner {
+			return p.Id
+		}
+	}
+
+	return ""
+}
+
+// passOwnership hands the room to another player when its owner leaves. It
+// must be called with s.lock held, after the owner's stream is removed.
+func (s *Server) passOwnership(roomKey, id string) {
+	if roomOwners[roomKey] != id {
+		return
+	}
+
+	delete(roomOwners, roomKey)
+	for other := range s.roomChatStreams[roomKey] {
+		roomOwners[roomKey] = other
+		return
+	}
+}
+
+func (s *Server) checkMuted(message *pb.MessageRequest) error {
+	s.lock.RLock()
+	until, ok := mutedUntil[message.Id]
+	s.lock.RUnlock()
+
+	if ok && time.Now().Before(until) {
+		return status.Errorf(codes.PermissionDenied, "you are muted for another %s", time.Until(until).Round(time.Second))
+	}
+
+	return nil
+}
+
+// filterProfanity masks or rejects words from PROFANITY_LIST_FILE depending on
+// PROFANITY_ACTION.
+func (s *Server) filterProfanity(message *pb.MessageRequest) (string, error) {
+	matches := matchTokens(tokenize(message.Content), profanity, isWordForm)
+	if len(matches) == 0 {
+		return message.Content, nil
+	}
+
+	if settings().ProfanityAction == profanityReject {
+		s.sendModerationNotice(message, pb.ModerationEvent_REJECTED, "Your message was not sent because it contains a blocked word.")
+		return "", status.Error(codes.InvalidArgument, "message contains a blocked word")
+	}
+
+	return maskTokens(message.Content, matches), nil
+}
+
+func (s *Server) broadcastModeration(r *pb.ModerationRequest, action pb.ModerationEvent_Action, what string) {
+	s.lock.RLock()
+	name := s.playerName(r.RoomKey, r.TargetId)
+	s.lock.RUnlock()
+
+	content := fmt.Sprintf("%s %s.", name, what)
+	if r.Reason != "" {
+		content = fmt.Sprintf("%s %s: %s", name, what, r.Reason)
+	}
+
+	m := s.buildSystemMessage(r.RoomKey, pb.MessageKind_MODERATION, content)
+	m.Payload = &pb.MessageResponse_Moderation{Moderation: &pb.ModerationEvent{
+		PlayerId: r.TargetId,
+		Name:     name,
+		Action:   action,
+		Reason:   r.Reason,
+	}}
+	s.shareMessage(r.RoomKey, m, nil)
+}
+
+// loadWordList reads one lowercased word per line from path, skipping blank
+// lines and lines starting with #. An empty path returns no words.
+func loadWordList(path string) []string {
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open word list %s: %v", path, err)
+	}
+	defer f.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		w := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if w != "" && !strings.HasPrefix(w, "#") {
+			words = append(words, w)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatalf("Failed to read word list %s: %v", path, err)
+	}
+
+	return words
+}
@@ -0,0 +1,195 @@
+package chatservice
+
+import (
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/richardjaytea/infipic/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// hintStream is implemented by streams that take hints, which only Play
+// streams do.
+type hintStream interface {
+	SendHint(*pb.HintEvent) error
+}
+
+// playConn is a player's Play stream. Broadcasts and answers go through the
+// same queue, so they reach the player in the order they were sent.
+type playConn struct {
+	*subscriber
+	stream pb.Chat_PlayServer
+}
+
+func (c *playConn) Send(m *pb.MessageResponse) error {
+	return c.send("", &pb.PlayEvent{Event: &pb.PlayEvent_Message{Message: m}})
+}
+
+func (c *playConn) SendHint(h *pb.HintEvent) error {
+	return c.send("", &pb.PlayEvent{Event: &pb.PlayEvent_Hint{Hint: h}})
+}
+
+func (c *playConn) send(ref string, e *pb.PlayEvent) error {
+	e.Ref = ref
+	return c.push(func() error { return c.stream.Send(e) })
+}
+
+func (c *playConn) fail(ref string, err error) error {
+	return c.send(ref, &pb.PlayEvent{Event: &pb.PlayEvent_Error{Error: status.Convert(err).Proto()}})
+}
+
+// playPlayers sends a Play player the player lists.
+type playPlayers struct {
+	*playConn
+}
+
+func (p playPlayers) Send(m *pb.PlayerListResponse) error {
+	return p.send("", &pb.PlayEvent{Event: &pb.PlayEvent_Players{Players: m}})
+}
+
+// Play joins the player to the room with the first request, then handles
+// their requests until they leave. Everything the player receives goes out on
+// the one stream, so it arrives in the order it was sent.
+func (s *Server) Play(stream pb.Chat_PlayServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	m := req.GetJoin()
+	if m == nil {
+		return status.Error(codes.FailedPrecondition, "the first request must be a join")
+	}
+	if err := s.validateCaller(stream.Context(), m, getMessagesMethod); err != nil {
+		return err
+	}
+
+	c := &playConn{subscriber: newSubscriber(stream.Context()), stream: stream}
+	defer c.close()
+
+	if err := s.join(m, c); err != nil {
+		return err
+	}
+	s.addPresence(m.RoomKey, m.Id, playPlayers{c})
+	defer s.removePresence(m.RoomKey, m.Id, playPlayers{c})
+	s.sendRound(c, m.RoomKey, m.Id)
+	go s.readPlay(c, m)
+
+	return s.keepAliveTillClose(m.Id, m.RoomKey)
+}
+
+// readPlay handles the player's requests in order until the stream fails, the
+// client closes it or sends nothing for HEARTBEAT_TIMEOUT, then cancels the
+// stream's context.
+func (s *Server) readPlay(c *playConn, m *pb.MessageStreamRequest) {
+	defer c.cancel()
+
+	var timeout *time.Timer
+	defer func() {
+		if timeout != nil {
+			timeout.Stop()
+		}
+	}()
+
+	for {
+		if timeout != nil {
+			timeout.Stop()
+		}
+		if d := settings().HeartbeatTimeout; d > 0 {
+			timeout = time.AfterFunc(d, func() {
+				log.Printf("Heartbeat Timed Out: %s", m.Id)
+				c.cancel()
+			})
+		}
+		req, err := c.stream.Recv()
+		if err != nil {
+			return
+		}
+
+		switch a := req.Action.(type) {
+		case *pb.PlayRequest_Message:
+			s.sendFromPlay(c, m, req.Ref, a.Message)
+		case *pb.PlayRequest_Heartbeat:
+			if a.Heartbeat.GetActive() {
+				s.markActive(m.RoomKey, m.Id)
+			}
+			c.send(req.Ref, &pb.PlayEvent{Event: &pb.PlayEvent_Heartbeat{Heartbeat: &pb.Heartbeat{}}})
+		case *pb.PlayRequest_Join:
+			c.fail(req.Ref, status.Error(codes.FailedPrecondition, "already joined"))
+		default:
+			c.fail(req.Ref, status.Error(codes.InvalidArgument, "action is required"))
+		}
+	}
+}
+
+// sendFromPlay is SendMessage for a Play player, with the same rate limits as
+// everyone else.
+func (s *Server) sendFromPlay(c *playConn, m *pb.MessageStreamRequest, ref string, message *pb.MessageRequest) {
+	message.Id, message.RoomKey = m.Id, m.RoomKey
+
+	if seconds := s.limitMessage(message); seconds > 0 {
+		c.fail(ref, errRateLimited(seconds))
+		return
+	}
+	r, err := s.SendMessage(c.Context(), message)
+	if err != nil {
+		c.fail(ref, err)
+		return
+	}
+
+	c.send(ref, &pb.PlayEvent{Event: &pb.PlayEvent_Sent{Sent: r}})
+}
+
+// sendRound tells a player who just joined about the round in progress, if
+// there is one.
+func (s *Server) sendRound(c *playConn, roomKey, id string) {
+	s.lock.RLock()
+	round := &pb.RoundStartEvent{
+		ImageUrl:  roomImages[roomKey],
+		WordCount: int32(len(roomWords[roomKey])),
+	}
+	hint := buildHint(roomKey, id)
+	s.lock.RUnlock()
+
+	if round.WordCount == 0 {
+		return
+	}
+	if err := c.send("", &pb.PlayEvent{Event: &pb.PlayEvent_Round{Round: round}}); err != nil {
+		log.Println(err)
+	}
+	if err := c.SendHint(hint); err != nil {
+		log.Println(err)
+	}
+}
+
+// sendHints sends each player in the room for which to returns true their
+// hint, or every player when to is nil. Only Play streams take hints.
+func (s *Server) sendHints(roomKey string, to func(id string) bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for id, stream := range s.roomChatStreams[roomKey] {
+		hs, ok := stream.(hintStream)
+		if !ok || (to != nil && !to(id)) {
+			continue
+		}
+		if err := hs.SendHint(buildHint(roomKey, id)); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// buildHint must be called with s.lock held.
+func buildHint(roomKey, id string) *pb.HintEvent {
+	var words []string
+	for _, w := range roomWords[roomKey] {
+		if !contains(userWords[id], w) {
+			w = strings.Repeat("_", utf8.RuneCountInString(w))
+		}
+		words = append(words, w)
+	}
+
+	return &pb.HintEvent{Words: words}
+}
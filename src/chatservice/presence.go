@@ -0,0 +1,152 @@
+package chatservice
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/richardjaytea/infipic/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// presenceStream is where a player's player lists go, a GetPresence or Play
+// stream or a WebSocket.
+type presenceStream interface {
+	Send(*pb.PlayerListResponse) error
+}
+
+type presenceStreamMap map[string]presenceStream
+
+func (s *Server) ListPlayers(ctx context.Context, r *pb.RoomRequest) (*pb.PlayerListResponse, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if _, ok := s.roomChatStreams[r.RoomKey]; !ok {
+		return nil, status.Errorf(codes.NotFound, "room %s does not exist", r.RoomKey)
+	}
+
+	return s.buildPlayerList(r.RoomKey), nil
+}
+
+// GetPresence sends the room's player list on subscribe and again whenever a
+// player joins, leaves, guesses a word or a new round starts.
+func (s *Server) GetPresence(r *pb.Client, stream pb.Chat_GetPresenceServer) error {
+	sub := newSubscriber(stream.Context())
+	defer sub.close()
+	ps := &presenceSubscriber{subscriber: sub, stream: stream}
+
+	s.lock.Lock()
+	if _, ok := s.roomPresenceStreams[r.RoomKey]; !ok {
+		s.lock.Unlock()
+		return status.Errorf(codes.NotFound, "room %s does not exist", r.RoomKey)
+	}
+	s.roomPresenceStreams[r.RoomKey][r.Id] = ps
+	ps.Send(s.buildPlayerList(r.RoomKey))
+	s.lock.Unlock()
+	log.Printf("Presence Stream Created: %s %s", r.RoomKey, r.Id)
+
+	var err error
+	select {
+	case <-sub.Context().Done():
+	case <-s.shutdown:
+		err = errRestarting
+	}
+
+	s.removePresence(r.RoomKey, r.Id, ps)
+	log.Printf("Presence Connection Disconnected: %s %s", r.RoomKey, r.Id)
+	return err
+}
+
+// addPresence sends the player the player list on stream from now on, in
+// place of any presence stream they had, starting with the current one. It is
+// called once the player has joined, so a join that fails, such as one by an
+// id already in the room, leaves the streams of the player alone.
+func (s *Server) addPresence(roomKey, id string, stream presenceStream) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.roomPresenceStreams[roomKey][id] = stream
+	if err := stream.Send(s.buildPlayerList(roomKey)); err != nil {
+		log.Println(err)
+	}
+}
+
+// removePresence removes the player's presence stream, unless it has already
+// been replaced by a stream of their resumed session.
+func (s *Server) removePresence(roomKey, id string, stream presenceStream) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.roomPresenceStreams[roomKey][id] == stream {
+		delete(s.roomPresenceStreams[roomKey], id)
+	}
+}
+
+// broadcastPresence sends the player list to the room, and this replica's
+// players to the other replicas sharing it.
+func (s *Server) broadcastPresence(roomKey string) {
+	s.sendPresence(roomKey)
+	s.publishRoster(roomKey)
+}
+
+// sendPresence sends the player list to the presence streams on this replica.
+func (s *Server) sendPresence(roomKey string) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	players := s.buildPlayerList(roomKey)
+	for _, stream := range s.roomPresenceStreams[roomKey] {
+		if err := stream.Send(players); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// buildPlayerList lists the players on every replica sharing the room. It
+// must be called with s.lock held.
+func (s *Server) buildPlayerList(roomKey string) *pb.PlayerListResponse {
+	players := append(s.localPlayers(roomKey), s.remotePlayers(roomKey)...)
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Name < players[j].Name
+	})
+
+	return &pb.PlayerListResponse{
+		RoomKey:        roomKey,
+		Players:        players,
+		WordSourceDown: wordSourceDown[roomKey],
+	}
+}
+
+// localPlayers lists the players connected to this replica. It must be called
+// with s.lock held.
+func (s *Server) localPlayers(roomKey string) []*pb.Player {
+	var players []*pb.Player
+	for id, stream := range s.roomChatStreams[roomKey] {
+		_, disconnected := stream.(*suspended)
+		players = append(players, &pb.Player{
+			Id:           id,
+			Name:         userNames[id],
+			Status:       playerStatus(roomKey, id),
+			WordsGuessed: int32(len(userWords[id])),
+			Team:         userTeams[id],
+			Owner:        roomOwners[roomKey] == id,
+			Disconnected: disconnected,
+			Afk:          isAFK(id),
+			Flagged:      isFlagged(id),
+		})
+	}
+
+	return players
+}
+
+func playerStatus(roomKey, id string) pb.PlayerStatus {
+	switch {
+	case len(roomWords[roomKey]) == 0:
+		return pb.PlayerStatus_IDLE
+	case len(userWords[id]) >= len(roomWords[roomKey]):
+		return pb.PlayerStatus_GUESSED_ALL
+	default:
+		return pb.PlayerStatus_GUESSING
+	}
+}
@@ -0,0 +1,136 @@
+package chatservice
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/richardjaytea/infipic/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	retryAfterKey = "retry-after"
+	sweepInterval = time.Minute
+)
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter keeps a token bucket per key that refills at rate tokens a
+// second up to burst.
+type rateLimiter struct {
+	lock    sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*tokenBucket
+}
+
+// newRateLimiter returns a limiter whose refilled buckets are swept until done
+// is closed.
+func newRateLimiter(rate, burst float64, done <-chan struct{}) *rateLimiter {
+	l := &rateLimiter{
+		rate:    rate,
+		burst:   burst,
+		buckets: make(map[string]*tokenBucket),
+	}
+	go l.sweep(done)
+	return l
+}
+
+// take uses up a token for key. It returns 0 if there was one, otherwise how
+// long until the next token is available.
+func (l *rateLimiter) take(key string, now time.Time) time.Duration {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// sweep removes buckets that have refilled, as they are the same as new ones,
+// every sweepInterval until done is closed.
+func (l *rateLimiter) sweep(done <-chan struct{}) {
+	t := time.NewTicker(sweepInterval)
+	defer t.Stop()
+
+	for {
+		var now time.Time
+		select {
+		case <-done:
+			return
+		case now = <-t.C:
+		}
+
+		l.lock.Lock()
+		for k, b := range l.buckets {
+			if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+				delete(l.buckets, k)
+			}
+		}
+		l.lock.Unlock()
+	}
+}
+
+// rateLimit is a unary interceptor limiting SendMessage per player and room.
+// Single word messages are possible guesses and use the stricter guess limit.
+func (s *Server) rateLimit(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	message, ok := req.(*pb.MessageRequest)
+	if !ok {
+		return handler(ctx, req)
+	}
+	if seconds := s.limitMessage(message); seconds > 0 {
+		grpc.SetTrailer(ctx, metadata.Pairs(retryAfterKey, strconv.Itoa(seconds)))
+		return nil, errRateLimited(seconds)
+	}
+
+	return handler(ctx, req)
+}
+
+// limitMessage takes a token from the sender's bucket. It returns 0 if there
+// was one, otherwise how many seconds to wait before sending again.
+func (s *Server) limitMessage(message *pb.MessageRequest) int {
+	limiter := s.messageLimiter
+	if isGuess(message.Content) {
+		limiter = s.guessLimiter
+	}
+
+	return int(math.Ceil(limiter.take(message.RoomKey+"/"+message.Id, time.Now()).Seconds()))
+}
+
+func errRateLimited(seconds int) error {
+	return status.Errorf(codes.ResourceExhausted, "too many messages, retry after %ds", seconds)
+}
+
+func newMessageLimiters(done <-chan struct{}) (*rateLimiter, *rateLimiter) {
+	cfg := settings()
+	return newRateLimiter(cfg.MessageRatePerSecond, cfg.MessageBurst, done),
+		newRateLimiter(cfg.GuessRatePerSecond, cfg.GuessBurst, done)
+}
+
+// isGuess reports whether content could match a word, which never has spaces.
+func isGuess(content string) bool {
+	m := strings.TrimSpace(content)
+	return m != "" && !strings.ContainsAny(m, " \t\n")
+}
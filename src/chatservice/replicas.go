@@ -0,0 +1,252 @@
+package chatservice
+
+import (
+	"log"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/richardjaytea/infipic/fanout"
+	"github.com/richardjaytea/infipic/pb"
+)
+
+// Chat replicas share rooms over the backplane. Each keeps the players
+// connected to it, and gets the same rounds from Image. What players do is
+// published to the room's subject for the other replicas:
+//
+//	deliveries  messages players on other replicas should see, such as chat,
+//	            joins, leaves and moderation, picked by an audience each
+//	            replica matches against its own players.
+//	rosters     a replica's players in the room, merged into player lists.
+//	            They are sent on every change and every rosterInterval, and
+//	            forgotten after rosterTTL, when a replica has gone.
+//	moderation  mutes and kicks of players on another replica.
+//
+// A player's calls must reach the replica holding their stream, which Play
+// and WebSocket connections do by carrying everything.
+const (
+	rosterInterval = 10 * time.Second
+	rosterTTL      = 3 * rosterInterval
+	// outboxSize is how many events can wait to be published before new ones
+	// are dropped.
+	outboxSize = 1024
+)
+
+// outboxes reports the events waiting to be published in /debug/vars.
+var outboxes = fanout.NewGroup("chat_backplane_outbox")
+
+// remoteRoster is a room's players on another replica, as of its last roster.
+type remoteRoster struct {
+	players []*pb.Player
+	at      time.Time
+}
+
+// remoteRosters holds the rosters of other replicas by room and replica.
+var remoteRosters map[string]map[string]remoteRoster
+
+func roomSubject(roomKey string) string {
+	return "chat.rooms." + roomKey
+}
+
+// shareMessage sends m to the players to picks, on this and every other
+// replica sharing the room.
+func (s *Server) shareMessage(roomKey string, m *pb.MessageResponse, to *pb.Audience) {
+	s.sendToPlayers(roomKey, m, to)
+	s.publish(roomKey, &pb.RoomEvent{Event: &pb.RoomEvent_Delivery{Delivery: &pb.Delivery{
+		Message:  m,
+		Audience: to,
+	}}})
+}
+
+// publish queues e for the other replicas sharing the room, so callers are not
+// held up by the backplane.
+func (s *Server) publish(roomKey string, e *pb.RoomEvent) {
+	e.Replica = id
+	e.RoomKey = roomKey
+	data, err := proto.Marshal(e)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	s.outbox.Push(func() error {
+		return s.backplane.Publish(roomSubject(roomKey), data)
+	})
+}
+
+// publishRoster tells the other replicas which of the room's players are
+// connected here.
+func (s *Server) publishRoster(roomKey string) {
+	s.lock.RLock()
+	players := s.localPlayers(roomKey)
+	s.lock.RUnlock()
+
+	s.publish(roomKey, &pb.RoomEvent{Event: &pb.RoomEvent_Roster{Roster: &pb.Roster{Players: players}}})
+}
+
+// subscribeRoom receives the room's events from the other replicas, and asks
+// them for their rosters.
+func (s *Server) subscribeRoom(roomKey string) {
+	if err := s.backplane.Subscribe(roomSubject(roomKey), s.receive); err != nil {
+		log.Printf("Failed to subscribe to %s: %v", roomKey, err)
+		return
+	}
+
+	s.publish(roomKey, &pb.RoomEvent{Event: &pb.RoomEvent_RosterRequest{RosterRequest: &empty.Empty{}}})
+}
+
+// receive handles an event from another replica. It is called in the order
+// events were published, so must not block.
+func (s *Server) receive(data []byte) {
+	var e pb.RoomEvent
+	if err := proto.Unmarshal(data, &e); err != nil {
+		log.Printf("Dropped backplane event: %v", err)
+		return
+	}
+	if e.Replica == id {
+		return
+	}
+
+	roomKey := e.RoomKey
+	s.lock.RLock()
+	_, ok := s.roomChatStreams[roomKey]
+	s.lock.RUnlock()
+	if !ok {
+		return
+	}
+
+	switch ev := e.Event.(type) {
+	case *pb.RoomEvent_Delivery:
+		m := ev.Delivery.GetMessage()
+		if m == nil {
+			return
+		}
+		// Numbered in this replica's sequence, so ids stay unique to its players.
+		m.MessageId = s.nextMessageId(roomKey)
+		s.sendToPlayers(roomKey, m, ev.Delivery.Audience)
+	case *pb.RoomEvent_Roster:
+		s.lock.Lock()
+		if len(ev.Roster.Players) > 0 {
+			remoteRosters[roomKey][e.Replica] = remoteRoster{players: ev.Roster.Players, at: time.Now()}
+		} else {
+			delete(remoteRosters[roomKey], e.Replica)
+		}
+		changed := s.settleOwnership(roomKey)
+		s.lock.Unlock()
+
+		if changed {
+			s.broadcastPresence(roomKey)
+		} else {
+			s.sendPresence(roomKey)
+		}
+	case *pb.RoomEvent_RosterRequest:
+		s.publishRoster(roomKey)
+	case *pb.RoomEvent_Moderation:
+		if ev.Moderation.Action == pb.ModerationEvent_BANNED {
+			s.banAddress(roomKey, ev.Moderation)
+		}
+		s.applyModeration(ev.Moderation)
+	}
+}
+
+// refreshRosters republishes this replica's rosters every rosterInterval and
+// forgets those of replicas that have stopped sending theirs.
+func (s *Server) refreshRosters() {
+	t := time.NewTicker(rosterInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-s.shutdown:
+			return
+		case <-t.C:
+		}
+
+		for _, roomKey := range rooms {
+			s.lock.Lock()
+			expired := false
+			for replica, r := range remoteRosters[roomKey] {
+				if time.Since(r.at) > rosterTTL {
+					log.Printf("Forgetting Replica: %s %s", roomKey, replica)
+					delete(remoteRosters[roomKey], replica)
+					expired = true
+				}
+			}
+			changed := s.settleOwnership(roomKey)
+			s.lock.Unlock()
+
+			if expired || changed {
+				s.sendPresence(roomKey)
+			}
+			s.publishRoster(roomKey)
+		}
+	}
+}
+
+// remotePlayers returns the room's players on other replicas, leaving out any
+// also connected here. It must be called with s.lock held.
+func (s *Server) remotePlayers(roomKey string) []*pb.Player {
+	var players []*pb.Player
+	for _, r := range remoteRosters[roomKey] {
+		for _, p := range r.players {
+			if _, local := s.roomChatStreams[roomKey][p.Id]; !local {
+				players = append(players, p)
+			}
+		}
+	}
+
+	return players
+}
+
+// remotePlayer returns the player if they are on another replica. It must be
+// called with s.lock held.
+func (s *Server) remotePlayer(roomKey, id string) *pb.Player {
+	for _, p := range s.remotePlayers(roomKey) {
+		if p.Id == id {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// playerName returns the name of a player on any replica. It must be called
+// with s.lock held.
+func (s *Server) playerName(roomKey, id string) string {
+	if p := s.remotePlayer(roomKey, id); p != nil {
+		return p.Name
+	}
+
+	return userNames[id]
+}
+
+// settleOwnership leaves a room shared between replicas with one owner. Of
+// the owners, the one on the replica with the lowest id keeps the room, and
+// with no owner anywhere that replica picks one of its players. It returns
+// whether this replica's owner changed, and must be called with s.lock held.
+func (s *Server) settleOwnership(roomKey string) bool {
+	var lowestOwner, lowestPlaying string
+	for replica, r := range remoteRosters[roomKey] {
+		if lowestPlaying == "" || replica < lowestPlaying {
+			lowestPlaying = replica
+		}
+		for _, p := range r.players {
+			if p.Owner && (lowestOwner == "" || replica < lowestOwner) {
+				lowestOwner = replica
+			}
+		}
+	}
+
+	switch {
+	case roomOwners[roomKey] != "" && lowestOwner != "" && lowestOwner < id:
+		delete(roomOwners, roomKey)
+		return true
+	case roomOwners[roomKey] == "" && lowestOwner == "" && (lowestPlaying == "" || id < lowestPlaying):
+		for other := range s.roomChatStreams[roomKey] {
+			roomOwners[roomKey] = other
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,162 +1,523 @@
-package main
+package chatservice
 
 import (
 	"context"
-	"flag"
+	"crypto/subtle"
 	"fmt"
 	"github.com/google/uuid"
-	c "github.com/richardjaytea/infipic/config"
-	"io"
+	"github.com/richardjaytea/infipic/auth"
+	"github.com/richardjaytea/infipic/backplane"
+	"github.com/richardjaytea/infipic/config"
+	"github.com/richardjaytea/infipic/discovery"
+	"github.com/richardjaytea/infipic/fanout"
+	"github.com/richardjaytea/infipic/healthcheck"
+	"github.com/richardjaytea/infipic/mtls"
 	"log"
-	"net"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/richardjaytea/infipic/pb"
+	"github.com/richardjaytea/infipic/retry"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/examples/data"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
-	tls                = flag.Bool("tls", false, "Connection uses TLS if true, else plain TCP")
-	certFile           = flag.String("cert_file", "", "The TLS cert file")
-	keyFile            = flag.String("key_file", "", "The TLS key file")
-	jsonDBFile         = flag.String("json_db_file", "", "A json file containing a list of features")
-	port               = flag.Int("port", 10000, "The server port")
-	emp                = empty.Empty{}
-	caFile             = flag.String("ca_file", "", "The file containing the CA root cert file")
-	serverAddrImage    = flag.String("server_addr_image", "localhost:10001", "The server address for the image service server")
-	serverAddrRoom     = flag.String("server_addr_room", "localhost:10003", "The server address for the room service server")
-	serverHostOverride = flag.String("server_host_override", "x.test.youtube.com", "The server name used to verify the hostname returned by the TLS handshake")
+	conf = config.DefaultChat()
+	// confLock guards the fields of conf that are reloaded while running.
+	confLock sync.RWMutex
 )
 
+var errRestarting = status.Error(codes.Unavailable, "server restarting")
+
 var (
 	id        = "service-" + uuid.NewString()
 	rooms     []string
 	roomWords map[string][]string
 	userWords map[string][]string
 	userNames map[string]string
+	userTeams map[string]string
+	// wordSourceDown is true for rooms that lost their word stream from Image.
+	wordSourceDown map[string]bool
+	// roomImages holds the image of each room's current round.
+	roomImages map[string]string
 )
 
-type messageStreamMap map[string]*pb.Chat_GetMessagesServer
+// messageStream is where a player's messages go, a GetMessages or Play stream
+// or a WebSocket, each behind a send queue.
+type messageStream interface {
+	Send(*pb.MessageResponse) error
+	Context() context.Context
+}
+
+type messageStreamMap map[string]messageStream
 
-type chatServer struct {
+type Server struct {
 	pb.UnimplementedChatServer
-	roomChatStreams map[string]messageStreamMap
-	imageClient     pb.ImageClient
-	roomClient      pb.RoomClient
+	// lock guards the stream maps along with roomWords, roomImages, userWords
+	// and userNames.
+	lock                sync.RWMutex
+	roomChatStreams     map[string]messageStreamMap
+	roomPresenceStreams map[string]presenceStreamMap
+	imageClient         pb.ImageClient
+	roomClient          pb.RoomClient
+	imageConn           *grpc.ClientConn
+	roomConn            *grpc.ClientConn
+	health              *healthcheck.Service
+	// certs is the certificate Room and Image are dialed with over TLS.
+	certs *mtls.Source
+	// shutdown is closed when the server is stopping, ending every stream.
+	shutdown chan struct{}
+
+	messageIdLock  sync.Mutex
+	roomMessageIds map[string]uint64
+
+	messageLimiter *rateLimiter
+	guessLimiter   *rateLimiter
+
+	// backplane carries room events to the other replicas, through outbox.
+	backplane backplane.Backplane
+	outbox    *fanout.Queue
+}
+
+func (s *Server) GetMessages(m *pb.MessageStreamRequest, stream pb.Chat_GetMessagesServer) error {
+	sub := newSubscriber(stream.Context())
+	defer sub.close()
+
+	if err := s.join(m, &messageSubscriber{subscriber: sub, stream: stream}); err != nil {
+		return err
+	}
+
+	return s.keepAliveTillClose(m.Id, m.RoomKey)
 }
 
-func (s *chatServer) GetMessages(m *pb.MessageStreamRequest, stream pb.Chat_GetMessagesServer) error {
-	s.roomChatStreams[m.RoomKey][m.Id] = &stream
-	userNames[m.Id] = m.Name
-	s.broadcastMessage(m.RoomKey, buildMessageResponse(c.VGetEnv("SYS_CHAT_NAME"), fmt.Sprintf("Welcome %s!", m.Name)))
-	log.Printf("Added Stream: %s", m.Id)
-	s.keepAliveTillClose(m.Id, m.RoomKey)
+// join adds the player to the room with stream receiving the room's messages,
+// and welcomes them. A player with the resume token of their session takes it
+// over instead, and is sent the messages they missed.
+func (s *Server) join(m *pb.MessageStreamRequest, stream messageStream) error {
+	address := playerAddress(stream.Context())
+	if err := s.checkBanned(stream.Context(), m, address); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	old, exists := s.roomChatStreams[m.RoomKey][m.Id]
+	resumed := exists && m.ResumeToken != "" &&
+		subtle.ConstantTimeCompare([]byte(m.ResumeToken), []byte(userSessions[m.Id])) == 1
+	if exists && !resumed {
+		s.lock.Unlock()
+		return status.Errorf(codes.AlreadyExists, "%s is already in the room, resume the session or wait for it to expire", m.Id)
+	}
+	if !resumed && strings.TrimSpace(m.Name) == "" {
+		s.lock.Unlock()
+		return status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	var missed []*pb.MessageResponse
+	if resumed {
+		// Ends the old stream, or the suspension if it dropped.
+		select {
+		case userKicks[m.Id] <- errSessionResumed:
+		default:
+		}
+		if sp, ok := old.(*suspended); ok {
+			missed = sp.messages()
+		}
+	} else {
+		userSessions[m.Id] = uuid.NewString()
+		userActive[m.Id] = true
+		userNames[m.Id] = m.Name
+		userTeams[m.Id] = m.Team
+		s.setOwner(m.RoomKey, m.Id)
+	}
+	s.roomChatStreams[m.RoomKey][m.Id] = stream
+	userKicks[m.Id] = make(chan error, 1)
+	userAddresses[m.Id] = address
+	name := userNames[m.Id]
+
+	// Sent with the lock held so nothing broadcast to the room comes first.
+	for _, v := range append([]*pb.MessageResponse{s.buildSession(m.RoomKey, m.Id, resumed)}, missed...) {
+		if err := stream.Send(v); err != nil {
+			log.Println(err)
+		}
+	}
+	s.lock.Unlock()
+
+	welcome := s.buildSystemMessage(m.RoomKey, pb.MessageKind_JOIN, fmt.Sprintf("Welcome %s!", name))
+	if resumed {
+		welcome.Content = fmt.Sprintf("%s is back.", name)
+	}
+	welcome.Payload = &pb.MessageResponse_Join{Join: &pb.JoinEvent{PlayerId: m.Id, Name: name, Resumed: resumed}}
+	s.shareMessage(m.RoomKey, welcome, nil)
+	s.broadcastPresence(m.RoomKey)
+	log.Printf("Added Stream: %s resumed %t", m.Id, resumed)
 	return nil
 }
 
-func (s *chatServer) SendMessage(ctx context.Context, message *pb.MessageRequest) (*pb.MatchWordResponse, error) {
+func (s *Server) SendMessage(ctx context.Context, message *pb.MessageRequest) (*pb.MatchWordResponse, error) {
 	m := strings.ToLower(strings.TrimSpace(message.Content))
+	now := time.Now()
+	s.markActive(message.RoomKey, message.Id)
+
+	s.lock.Lock()
+	guessing := isGuess(m) && playerStatus(message.RoomKey, message.Id) == pb.PlayerStatus_GUESSING
+	if guessing {
+		if err := checkGuess(ctx, message.Id, now); err != nil {
+			s.lock.Unlock()
+			return nil, err
+		}
+		if recordGuess(message.Id, m, contains(roomWords[message.RoomKey], m), now) {
+			// Deferred until s.lock is released, on every path below.
+			defer s.reportFlagged(message.RoomKey, message.Id)
+		}
+	}
 
 	if contains(roomWords[message.RoomKey], m) {
-		stream := s.roomChatStreams[message.RoomKey][message.Id]
 		if contains(userWords[message.Id], m) {
-			(*stream).Send(buildMessageResponse(c.VGetEnv("SYS_CHAT_NAME"), "You have already correctly guessed this word!"))
+			result := s.buildGuessResult(message.RoomKey, message.Id, m, true)
+			s.lock.Unlock()
+			s.sendToPlayer(message.RoomKey, message.Id, result)
 		} else {
 			userWords[message.Id] = append(userWords[message.Id], m)
-			(*stream).Send(buildMessageResponse(c.VGetEnv("SYS_CHAT_NAME"), "Your guess is correct!"))
+			result := s.buildGuessResult(message.RoomKey, message.Id, m, false)
+			done := playerStatus(message.RoomKey, message.Id) == pb.PlayerStatus_GUESSED_ALL && s.everyoneGuessed(message.RoomKey)
+			s.lock.Unlock()
+			s.sendToPlayer(message.RoomKey, message.Id, result)
+			if done {
+				s.shareMessage(message.RoomKey, s.buildSystemMessage(message.RoomKey, pb.MessageKind_SYSTEM, "Everyone has guessed every word!"), nil)
+			}
+			s.sendHints(message.RoomKey, func(other string) bool {
+				return other == message.Id
+			})
+			s.broadcastPresence(message.RoomKey)
 			return &pb.MatchWordResponse{Match: true}, nil
 		}
 	} else {
-		response := buildMessageResponse(userNames[message.Id], message.Content)
-		s.broadcastMessage(message.RoomKey, response)
+		s.lock.Unlock()
+		if err := s.deliverMessage(message); err != nil {
+			return nil, err
+		}
 	}
 
 	return &pb.MatchWordResponse{Match: false}, nil
 }
 
-func (s *chatServer) broadcastMessage(roomKey string, m *pb.MessageResponse) {
-	for _, stream := range s.roomChatStreams[roomKey] {
-		if stream != nil && *stream != nil {
-			if err := (*stream).Send(m); err != nil {
-				log.Println(err)
-			}
+func (s *Server) broadcastMessage(roomKey string, m *pb.MessageResponse) {
+	s.sendToPlayers(roomKey, m, nil)
+}
+
+func (s *Server) sendToPlayer(roomKey, id string, m *pb.MessageResponse) {
+	s.sendToPlayers(roomKey, m, &pb.Audience{Ids: []string{id}})
+}
+
+// sendToPlayers sends m to every player in the room that to picks, or to all
+// of them when to is nil.
+func (s *Server) sendToPlayers(roomKey string, m *pb.MessageResponse, to *pb.Audience) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for id, stream := range s.roomChatStreams[roomKey] {
+		if to != nil && !includes(roomKey, to, id) {
+			continue
+		}
+		if err := stream.Send(m); err != nil {
+			log.Println(err)
 		}
 	}
 }
 
-func buildMessageResponse(name, content string) *pb.MessageResponse {
+func (s *Server) buildMessageResponse(roomKey, name, content string) *pb.MessageResponse {
+	now := time.Now()
+	sentAt, err := ptypes.TimestampProto(now)
+	if err != nil {
+		log.Println(err)
+	}
+
 	return &pb.MessageResponse{
 		Name:      name,
 		Content:   content,
-		Timestamp: time.Now().Format(time.RFC822),
+		Timestamp: now.Format(time.RFC822),
+		SentAt:    sentAt,
+		MessageId: s.nextMessageId(roomKey),
+		Kind:      pb.MessageKind_PLAYER,
 	}
 }
 
-func (s *chatServer) keepAliveTillClose(id string, roomKey string) {
-	stream := *s.roomChatStreams[roomKey][id]
+// buildSystemMessage builds a message sent by the system chat name. Callers set
+// the matching Payload for kinds that carry one.
+func (s *Server) buildSystemMessage(roomKey string, kind pb.MessageKind, content string) *pb.MessageResponse {
+	m := s.buildMessageResponse(roomKey, settings().SysChatName, content)
+	m.Kind = kind
+	return m
+}
+
+func (s *Server) buildGuessResult(roomKey, id, word string, alreadyGuessed bool) *pb.MessageResponse {
+	content := "Your guess is correct!"
+	if alreadyGuessed {
+		content = "You have already correctly guessed this word!"
+	}
+
+	m := s.buildSystemMessage(roomKey, pb.MessageKind_GUESS_RESULT, content)
+	m.Payload = &pb.MessageResponse_GuessResult{GuessResult: &pb.GuessResultEvent{
+		Correct:        true,
+		AlreadyGuessed: alreadyGuessed,
+		Word:           word,
+		Remaining:      int32(len(roomWords[roomKey]) - len(userWords[id])),
+	}}
+	return m
+}
+
+// nextMessageId returns the next id in the room's message sequence, starting at 1.
+func (s *Server) nextMessageId(roomKey string) uint64 {
+	s.messageIdLock.Lock()
+	defer s.messageIdLock.Unlock()
+
+	s.roomMessageIds[roomKey]++
+	return s.roomMessageIds[roomKey]
+}
+
+// keepAliveTillClose blocks until the player disconnects or is kicked. A
+// player who drops is suspended for the resume grace period, anyone else is
+// removed from the room. It returns the status to close the stream with.
+func (s *Server) keepAliveTillClose(id string, roomKey string) error {
+	s.lock.RLock()
+	stream := s.roomChatStreams[roomKey][id]
+	kick := userKicks[id]
+	s.lock.RUnlock()
+
+	var err error
 	select {
 	case <-stream.Context().Done():
-		// TODO: Lock map for deletion?
-		delete(s.roomChatStreams[roomKey], id)
-		log.Printf("Connection Disconnected: %s", id)
+		if s.suspend(roomKey, id, stream) {
+			return nil
+		}
+	case err = <-kick:
+	case <-s.shutdown:
+		err = errRestarting
+	}
+
+	s.leave(roomKey, id, stream)
+	return err
+}
+
+// leave removes the player from the room, unless stream is no longer theirs
+// because they have resumed their session elsewhere.
+func (s *Server) leave(roomKey, id string, stream messageStream) {
+	s.lock.Lock()
+	if s.roomChatStreams[roomKey][id] != stream {
+		s.lock.Unlock()
 		return
 	}
+	delete(s.roomChatStreams[roomKey], id)
+	name := userNames[id]
+	delete(userNames, id)
+	delete(userWords, id)
+	delete(userTeams, id)
+	delete(userLeaks, id)
+	delete(userKicks, id)
+	delete(userGuesses, id)
+	delete(userSessions, id)
+	delete(userActive, id)
+	delete(userIdleRounds, id)
+	delete(mutedUntil, id)
+	delete(userAddresses, id)
+	s.passOwnership(roomKey, id)
+	s.lock.Unlock()
+
+	leave := s.buildSystemMessage(roomKey, pb.MessageKind_LEAVE, fmt.Sprintf("%s has left.", name))
+	leave.Payload = &pb.MessageResponse_Leave{Leave: &pb.LeaveEvent{PlayerId: id, Name: name}}
+	s.shareMessage(roomKey, leave, nil)
+	s.broadcastPresence(roomKey)
+	log.Printf("Connection Disconnected: %s", id)
 }
 
-func (s *chatServer) getImageWord() {
+// getImageWord starts a supervised word subscription for every room.
+func (s *Server) getImageWord() {
 	for _, v := range rooms {
-		stream, err := s.imageClient.GetImageAndWords(
-			context.Background(),
-			&pb.Client{
-				Id:      id,
-				RoomKey: v,
-			})
-		if err != nil {
-			log.Fatalf("%v.GetWord(_) = _, %v", s.imageClient, err)
+		go s.superviseWords(v)
+	}
+}
+
+// superviseWords keeps the room subscribed to its words, reconnecting with
+// backoff whenever the stream ends. The room keeps chatting in the meantime
+// and is marked as having no word source.
+func (s *Server) superviseWords(roomKey string) {
+	var b retry.Backoff
+	for {
+		received, err := s.subscribeWords(roomKey)
+		if received {
+			b.Reset()
 		}
-		go keepWordUpdated(stream, v)
+		s.setWordSourceDown(roomKey, true)
+
+		d := b.Next()
+		log.Printf("Word stream for %s ended, reconnecting in %s: %v", roomKey, d.Round(time.Millisecond), err)
+		time.Sleep(d)
+	}
+}
+
+// subscribeWords receives words for the room until the stream ends, and
+// reports whether any were received.
+func (s *Server) subscribeWords(roomKey string) (bool, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ctx = auth.WithToken(ctx, auth.Sign(settings().AuthSecret, id, roomKey))
+	stream, err := s.imageClient.GetImageAndWords(ctx, &pb.Client{
+		Id:      id,
+		RoomKey: roomKey,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return s.keepWordUpdated(stream, roomKey)
+}
+
+// Drain tells every room the server is restarting and ends all streams.
+func (s *Server) Drain() {
+	s.lock.RLock()
+	var keys []string
+	for k := range s.roomChatStreams {
+		keys = append(keys, k)
+	}
+	s.lock.RUnlock()
+
+	for _, k := range keys {
+		s.broadcastMessage(k, s.buildSystemMessage(k, pb.MessageKind_SYSTEM, "The server is restarting, please reconnect in a moment."))
 	}
+	close(s.shutdown)
 }
 
-func (s *chatServer) getRooms() {
+func (s *Server) getRooms() error {
 	r, err := s.roomClient.GetRooms(context.Background(), &empty.Empty{})
 	if err != nil {
-		log.Fatal("error in trying to get rooms from room service")
-		return
+		return err
 	}
 
+	rooms = nil
 	for _, v := range r.Rooms {
 		rooms = append(rooms, v.GetKey())
 	}
+
+	return nil
 }
 
-func keepWordUpdated(stream pb.Image_GetImageAndWordsClient, roomKey string) {
+// keepWordUpdated starts a new round whenever the words change. The image
+// service sends the current words first on every subscribe, so after a
+// reconnect an unchanged round carries on with players' guesses intact.
+func (s *Server) keepWordUpdated(stream pb.Image_GetImageAndWordsClient, roomKey string) (bool, error) {
+	received := false
 	for {
 		word, err := stream.Recv()
+		if err != nil {
+			return received, err
+		}
+		received = true
+		s.setWordSourceDown(roomKey, false)
+
+		s.lock.Lock()
+		previous := roomWords[roomKey]
+		if sameWords(previous, word.GetWords()) {
+			s.lock.Unlock()
+			continue
+		}
+		var idle []string
+		if len(previous) > 0 {
+			idle = s.countIdleRounds(roomKey)
+		}
+		s.clearWords(roomKey)
+		roomWords[roomKey] = word.GetWords()
+		roomImages[roomKey] = word.GetContent()
+		s.lock.Unlock()
 
-		if err == io.EOF {
-			break
+		for _, id := range idle {
+			log.Printf("Disconnecting Idle Player: %s %s", roomKey, id)
+			s.kick(id, errIdle)
 		}
-		if err != nil {
-			log.Fatalf("keepWordUpdated(_) = _, %v", err)
+		if len(previous) > 0 {
+			end := s.buildSystemMessage(roomKey, pb.MessageKind_ROUND_END, fmt.Sprintf("The words were: %s", strings.Join(previous, ", ")))
+			end.Payload = &pb.MessageResponse_RoundEnd{RoundEnd: &pb.RoundEndEvent{Words: previous}}
+			s.broadcastMessage(roomKey, end)
 		}
 
-		clearWords(roomKey)
-		roomWords[roomKey] = word.GetWords()
+		start := s.buildSystemMessage(roomKey, pb.MessageKind_ROUND_START, "A new round has started!")
+		start.Payload = &pb.MessageResponse_RoundStart{RoundStart: &pb.RoundStartEvent{
+			ImageUrl:  word.GetContent(),
+			WordCount: int32(len(word.GetWords())),
+		}}
+		s.broadcastMessage(roomKey, start)
+		s.sendHints(roomKey, nil)
+		s.broadcastPresence(roomKey)
 	}
 }
 
-func clearWords(roomKey string) {
-	var s []string
+// setWordSourceDown marks whether the room has lost its word stream and tells
+// the room when that changes.
+func (s *Server) setWordSourceDown(roomKey string, down bool) {
+	s.lock.Lock()
+	changed := wordSourceDown[roomKey] != down
+	wordSourceDown[roomKey] = down
+	s.reportWordSource()
+	s.lock.Unlock()
+
+	if !changed {
+		return
+	}
+
+	content := "The word source is back."
+	if down {
+		content = "The word source is unavailable, the current round carries on until it is back."
+	}
+	s.broadcastMessage(roomKey, s.buildSystemMessage(roomKey, pb.MessageKind_SYSTEM, content))
+	s.broadcastPresence(roomKey)
+}
+
+// reportWordSource marks Image as down in the pb.Chat/image health status
+// while any room has no word stream. Rooms chat on without words, so pb.Chat
+// stays SERVING. It must be called with s.lock held.
+func (s *Server) reportWordSource() {
+	var down int
+	for _, v := range wordSourceDown {
+		if v {
+			down++
+		}
+	}
+
+	var err error
+	if down > 0 {
+		err = fmt.Errorf("no word stream for %d of %d rooms", down, len(wordSourceDown))
+	}
+	s.health.Set("image", err)
+}
+
+func sameWords(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// clearWords resets the room's words and the guesses of players in the room.
+// It must be called with s.lock held.
+func (s *Server) clearWords(roomKey string) {
+	var w []string
 	delete(roomWords, roomKey)
-	roomWords[roomKey] = s
+	roomWords[roomKey] = w
 
-	for i, _ := range userWords {
+	for i := range s.roomChatStreams[roomKey] {
 		userWords[i] = nil
+		resetGuesses(i)
 	}
 }
 
@@ -170,84 +531,165 @@ func contains(s []string, c string) bool {
 	return false
 }
 
-func newServer() *chatServer {
-	s := &chatServer{
-		roomChatStreams: make(map[string]messageStreamMap),
+// New returns the Chat service. Rooms open once Start has connected to Room.
+func New(hs *healthcheck.Server) *Server {
+	bp, err := backplane.Open(settings().BackplaneURL)
+	if err != nil {
+		log.Fatalf("Failed to open backplane: %v", err)
+	}
+
+	s := &Server{
+		roomChatStreams:     make(map[string]messageStreamMap),
+		roomPresenceStreams: make(map[string]presenceStreamMap),
+		roomMessageIds:      make(map[string]uint64),
+		health:              hs.Service("pb.Chat", "room"),
+		shutdown:            make(chan struct{}),
+		backplane:           bp,
+		outbox:              outboxes.NewQueue(outboxSize, fanout.Drop, nil),
 	}
+	s.health.Optional("image")
+	s.messageLimiter, s.guessLimiter = newMessageLimiters(s.shutdown)
 	roomWords = make(map[string][]string)
+	roomImages = make(map[string]string)
 	userWords = make(map[string][]string)
 	userNames = make(map[string]string)
+	userTeams = make(map[string]string)
+	userSessions = make(map[string]string)
+	userActive = make(map[string]bool)
+	userIdleRounds = make(map[string]int)
+	wordSourceDown = make(map[string]bool)
+	userLeaks = make(map[string]int)
+	userKicks = make(map[string]chan error)
+	userGuesses = make(map[string]*guessState)
+	mutedUntil = make(map[string]time.Time)
+	roomOwners = make(map[string]string)
+	userAddresses = make(map[string]string)
+	remoteRosters = make(map[string]map[string]remoteRoster)
+	profanity = loadWordList(settings().ProfanityListFile)
 
-	for _, v := range rooms {
-		s.roomChatStreams[v] = messageStreamMap{}
+	return s
+}
+
+// Start connects to Room and Image, opens the rooms and subscribes to their
+// words. opts are added to the options used to dial them.
+func (s *Server) Start(opts ...grpc.DialOption) {
+	s.connectServices(opts)
+}
+
+// ServerOptions returns the interceptors Chat needs on its grpc.Server. They
+// leave calls to other services alone, so the server can be shared.
+func (s *Server) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.validateUnary, s.rateLimit),
+		grpc.ChainStreamInterceptor(s.validateStream),
 	}
+}
 
-	s.connectServices()
+// Close closes the backplane and the connections to Room and Image.
+func (s *Server) Close() error {
+	if s.certs != nil {
+		s.certs.Close()
+	}
+	s.outbox.Close()
+	s.outbox.Wait()
+	if err := s.backplane.Close(); err != nil {
+		return err
+	}
 
-	for _, v := range rooms {
-		s.roomChatStreams[v] = messageStreamMap{}
+	for _, conn := range []*grpc.ClientConn{s.imageConn, s.roomConn} {
+		if conn == nil {
+			continue
+		}
+		if err := conn.Close(); err != nil {
+			return err
+		}
 	}
 
-	return s
+	return nil
 }
 
-func (s *chatServer) connectServices() {
-	flag.Parse()
-	var opts []grpc.DialOption
-	if *tls {
-		if *caFile == "" {
-			*caFile = data.Path("x509/ca_cert.pem")
-		}
-		creds, err := credentials.NewClientTLSFromFile(*caFile, *serverHostOverride)
+func (s *Server) connectServices(opts []grpc.DialOption) {
+	cfg := settings()
+	creds := func(string) grpc.DialOption {
+		return grpc.WithInsecure()
+	}
+	if cfg.TLS {
+		certs, err := mtls.Load(cfg.CertFile, cfg.KeyFile, cfg.CAFile, cfg.TrustDomain)
 		if err != nil {
-			log.Fatalf("Failed to create TLS credentials %v", err)
+			log.Fatalf("Failed to load TLS certificates: %v", err)
+		}
+		s.certs = certs
+		// Each service dialed must prove it is the one expected.
+		creds = func(service string) grpc.DialOption {
+			return grpc.WithTransportCredentials(certs.ClientCredentials(service))
 		}
-		opts = append(opts, grpc.WithTransportCredentials(creds))
-	} else {
-		opts = append(opts, grpc.WithInsecure())
 	}
 
-	opts = append(opts, grpc.WithBlock())
-	conn, err := grpc.Dial(*serverAddrRoom, opts...)
+	conn, err := discovery.Dial(cfg.ServerAddrRoom, "pb.Room", append(opts, creds("room"))...)
 	if err != nil {
 		log.Fatalf("fail to dial: %v", err)
 	}
 
+	s.roomConn = conn
 	s.roomClient = pb.NewRoomClient(conn)
-	s.getRooms()
+	// Chat can do nothing without the rooms, so waits for Room however long
+	// it takes, unready until then.
+	for {
+		err := healthcheck.WaitReady(conn, "pb.Room", cfg.ReadyTimeout)
+		if err == nil {
+			break
+		}
+		log.Printf("Room not ready after %s, still waiting: %v", cfg.ReadyTimeout, err)
+		select {
+		case <-s.shutdown:
+			return
+		default:
+		}
+	}
+	retry.Do(context.Background(), "get rooms", s.getRooms)
+
+	s.lock.Lock()
+	for _, v := range rooms {
+		s.roomChatStreams[v] = messageStreamMap{}
+		s.roomPresenceStreams[v] = presenceStreamMap{}
+		remoteRosters[v] = make(map[string]remoteRoster)
+		wordSourceDown[v] = true
+	}
+	s.reportWordSource()
+	s.lock.Unlock()
+	for _, v := range rooms {
+		s.subscribeRoom(v)
+	}
+	go s.refreshRosters()
+	go s.health.Poll(context.Background(), "room", func(ctx context.Context) error {
+		return healthcheck.Check(ctx, s.roomConn, "pb.Room")
+	})
 
-	conn, err = grpc.Dial(*serverAddrImage, opts...)
+	conn, err = discovery.Dial(cfg.ServerAddrImage, "pb.Image", append(opts, creds("image"))...)
 	if err != nil {
 		log.Fatalf("fail to dial: %v", err)
 	}
-	// defer conn.Close()
+	s.imageConn = conn
 	s.imageClient = pb.NewImageClient(conn)
+	// Rooms chat without words until Image is up, so carry on either way.
+	if err := healthcheck.WaitReady(conn, "pb.Image", cfg.ReadyTimeout); err != nil {
+		log.Printf("Image not ready after %s, rooms start without words: %v", cfg.ReadyTimeout, err)
+	}
 	s.getImageWord()
 }
 
-func main() {
-	flag.Parse()
-	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", *port))
-	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
-	}
-	var opts []grpc.ServerOption
-	if *tls {
-		if *certFile == "" {
-			*certFile = data.Path("x509/server_cert.pem")
-		}
-		if *keyFile == "" {
-			*keyFile = data.Path("x509/server_key.pem")
-		}
-		creds, err := credentials.NewServerTLSFromFile(*certFile, *keyFile)
-		if err != nil {
-			log.Fatalf("Failed to generate credentials %v", err)
-		}
-		opts = []grpc.ServerOption{grpc.Creds(creds)}
-	}
-	grpcServer := grpc.NewServer(opts...)
-	pb.RegisterChatServer(grpcServer, newServer())
-	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("failed to serve Chat: %v", err)
-	}
+// Configure replaces the config the service runs with.
+func Configure(c config.Chat) {
+	confLock.Lock()
+	defer confLock.Unlock()
+
+	conf = c
+}
+
+// settings returns a copy of the config that is safe to use during reloads.
+func settings() config.Chat {
+	confLock.RLock()
+	defer confLock.RUnlock()
+
+	return conf
 }
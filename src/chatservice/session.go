@@ -0,0 +1,115 @@
+package chatservice
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/richardjaytea/infipic/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxMissedMessages is how many messages a dropped player is kept, older ones
+// are let go.
+const maxMissedMessages = 256
+
+var errSessionResumed = status.Error(codes.Aborted, "the session was resumed on another connection")
+
+// userSessions holds each player's resume token.
+var userSessions map[string]string
+
+// suspended stands in for the stream of a player who dropped, keeping the
+// messages they miss until they resume or the grace period is over.
+type suspended struct {
+	lock   sync.Mutex
+	missed []*pb.MessageResponse
+}
+
+func (sp *suspended) Send(m *pb.MessageResponse) error {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	if len(sp.missed) == maxMissedMessages {
+		sp.missed = sp.missed[1:]
+	}
+	sp.missed = append(sp.missed, m)
+	return nil
+}
+
+func (sp *suspended) Context() context.Context {
+	return context.Background()
+}
+
+func (sp *suspended) messages() []*pb.MessageResponse {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	return sp.missed
+}
+
+// suspend keeps the place of a player whose stream dropped for RESUME_GRACE.
+// It returns false if there is no grace period or stream is no longer theirs,
+// and the player should leave.
+func (s *Server) suspend(roomKey, id string, stream messageStream) bool {
+	grace := settings().ResumeGrace
+	if grace <= 0 {
+		return false
+	}
+
+	sp := &suspended{}
+	s.lock.Lock()
+	if s.roomChatStreams[roomKey][id] != stream {
+		s.lock.Unlock()
+		return false
+	}
+	s.roomChatStreams[roomKey][id] = sp
+	kick := userKicks[id]
+	s.lock.Unlock()
+
+	s.broadcastPresence(roomKey)
+	log.Printf("Suspended Stream: %s for %s", id, grace)
+	go s.expire(roomKey, id, sp, kick, grace)
+	return true
+}
+
+// expire removes a suspended player once the grace period is over, or
+// straight away if they are kicked. Resuming also sends on kick, but by then
+// sp is no longer theirs and leave does nothing.
+func (s *Server) expire(roomKey, id string, sp *suspended, kick chan error, grace time.Duration) {
+	t := time.NewTimer(grace)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+	case <-kick:
+	case <-s.shutdown:
+	}
+
+	s.leave(roomKey, id, sp)
+}
+
+// heartbeatInterval is how often Play and WebSocket clients are asked to send
+// heartbeats, leaving room for two to go missing before HEARTBEAT_TIMEOUT.
+func heartbeatInterval() time.Duration {
+	return settings().HeartbeatTimeout / 3
+}
+
+// buildSession builds the message telling a player how to resume their
+// session. It must be called with s.lock held.
+func (s *Server) buildSession(roomKey, id string, resumed bool) *pb.MessageResponse {
+	content := "You can resume this session if you drop."
+	if resumed {
+		content = "Your session has been resumed."
+	}
+
+	m := s.buildSystemMessage(roomKey, pb.MessageKind_SESSION, content)
+	m.Payload = &pb.MessageResponse_Session{Session: &pb.SessionEvent{
+		ResumeToken:      userSessions[id],
+		GraceSeconds:     int32(settings().ResumeGrace.Seconds()),
+		Resumed:          resumed,
+		HeartbeatSeconds: int32(heartbeatInterval().Seconds()),
+	}}
+	return m
+}
@@ -0,0 +1,76 @@
+package chatservice
+
+import (
+	"context"
+
+	"github.com/richardjaytea/infipic/fanout"
+	"github.com/richardjaytea/infipic/pb"
+)
+
+// sendQueues reports the depth of every player's queue in /debug/vars.
+var sendQueues = fanout.NewGroup("chat_send_queues")
+
+// subscriber is a player's connection as seen by broadcasts. Sends are queued
+// and made by the queue's goroutine, so a slow player never holds up the room.
+// Its context ends with the connection, or when SLOW_CONSUMER_POLICY
+// disconnects it, which drops the player as if their connection had.
+type subscriber struct {
+	q      *fanout.Queue
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newSubscriber(ctx context.Context) *subscriber {
+	cfg := settings()
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &subscriber{
+		q:      sendQueues.NewQueue(cfg.SendQueueSize, cfg.SlowConsumerPolicy, cancel),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (sub *subscriber) Context() context.Context {
+	return sub.ctx
+}
+
+// push queues send. A send that doesn't fit is counted in the metrics rather
+// than reported to the caller, which is broadcasting to the whole room.
+func (sub *subscriber) push(send func() error) error {
+	sub.q.Push(send)
+	return nil
+}
+
+// close stops queueing sends and waits for the ones already queued to be made,
+// for up to fanout.FlushTimeout, so the last of them, such as a restart
+// notice, goes out before the stream ends.
+func (sub *subscriber) close() {
+	sub.q.Close()
+	sub.cancel()
+	sub.q.Wait()
+}
+
+// messageSubscriber queues the messages of a GetMessages stream.
+type messageSubscriber struct {
+	*subscriber
+	stream interface {
+		Send(*pb.MessageResponse) error
+	}
+}
+
+func (m *messageSubscriber) Send(r *pb.MessageResponse) error {
+	return m.push(func() error { return m.stream.Send(r) })
+}
+
+// presenceSubscriber queues the player lists of a GetPresence stream.
+type presenceSubscriber struct {
+	*subscriber
+	stream interface {
+		Send(*pb.PlayerListResponse) error
+	}
+}
+
+func (p *presenceSubscriber) Send(r *pb.PlayerListResponse) error {
+	return p.push(func() error { return p.stream.Send(r) })
+}
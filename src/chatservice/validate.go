@@ -0,0 +1,87 @@
+package chatservice
+
+import (
+	"context"
+	"strings"
+
+	"github.com/richardjaytea/infipic/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// chatMethods prefixes every Chat method, other services on the same
+	// server are not checked.
+	chatMethods       = "/pb.Chat/"
+	getMessagesMethod = chatMethods + "GetMessages"
+)
+
+// roomCaller is implemented by every request that names its caller.
+type roomCaller interface {
+	GetId() string
+	GetRoomKey() string
+}
+
+func (s *Server) validateUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.validateCaller(ctx, req, info.FullMethod); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (s *Server) validateStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &validatingStream{
+		ServerStream: ss,
+		validate: func(m interface{}) error {
+			return s.validateCaller(ss.Context(), m, info.FullMethod)
+		},
+	})
+}
+
+// validatingStream checks the request of a server streaming RPC once the
+// handler has received it.
+type validatingStream struct {
+	grpc.ServerStream
+	validate func(m interface{}) error
+}
+
+func (v *validatingStream) RecvMsg(m interface{}) error {
+	if err := v.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	return v.validate(m)
+}
+
+// validateCaller makes sure the room exists, the token was issued for the
+// caller and room, and, for everything but GetMessages, that the caller has
+// an open GetMessages stream in the room.
+func (s *Server) validateCaller(ctx context.Context, req interface{}, method string) error {
+	r, ok := req.(roomCaller)
+	if !ok || !strings.HasPrefix(method, chatMethods) {
+		return nil
+	}
+
+	if r.GetId() == "" || r.GetRoomKey() == "" {
+		return status.Error(codes.InvalidArgument, "id and roomKey are required")
+	}
+
+	s.lock.RLock()
+	streams, roomFound := s.roomChatStreams[r.GetRoomKey()]
+	_, sessionFound := streams[r.GetId()]
+	s.lock.RUnlock()
+
+	if !roomFound {
+		return status.Errorf(codes.NotFound, "room %s does not exist", r.GetRoomKey())
+	}
+	if !auth.Verify(settings().AuthSecret, r.GetId(), r.GetRoomKey(), auth.FromContext(ctx)) {
+		return status.Error(codes.PermissionDenied, "invalid client token")
+	}
+	if method != getMessagesMethod && !sessionFound {
+		return status.Errorf(codes.PermissionDenied, "no open GetMessages stream in room %s", r.GetRoomKey())
+	}
+
+	return nil
+}
@@ -0,0 +1,313 @@
+package chatservice
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/richardjaytea/infipic/auth"
+	"github.com/richardjaytea/infipic/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// A player can play over a WebSocket instead of the gRPC streams, in the same
+// rooms as gRPC players. They connect to
+//
+//	GET /v1/rooms/{roomKey}/ws?id=&name=&team=&token=&resume=
+//
+// with the id and token from Authenticate, the token also being accepted in
+// the X-Client-Token header. resume is the resume token of a dropped session.
+// Every frame both ways is a JSON envelope
+//
+//	{"type": "send", "ref": "1", "data": {"content": "apple"}}
+//
+// Clients send
+//
+//	send       data is a MessageRequest, its id and roomKey are taken from
+//	           the connection. ref is optional and echoed on the reply.
+//	heartbeat  data is an optional Heartbeat, answered with an empty one.
+//
+// and the server sends
+//
+//	message    data is a MessageResponse, as on the GetMessages stream: chat,
+//	           joins, leaves, guess results and round events.
+//	players    data is a PlayerListResponse, as on the GetPresence stream.
+//	sent       data is the MatchWordResponse to the send with the same ref.
+//	heartbeat  answers the heartbeat with the same ref.
+//	error      data is a google.rpc.Status. With a ref it answers that
+//	           request, without one the server is closing the connection.
+//
+// Field names are those of services.proto and enums are sent by name. The
+// server pings every heartbeat interval and drops connections it hears
+// nothing from, pongs included, for HEARTBEAT_TIMEOUT.
+const (
+	envelopeSend      = "send"
+	envelopeMessage   = "message"
+	envelopePlayers   = "players"
+	envelopeSent      = "sent"
+	envelopeError     = "error"
+	envelopeHeartbeat = "heartbeat"
+
+	webSocketSuffix = "/ws"
+	// webSocketWriteTimeout stops a stalled client from holding up the room.
+	webSocketWriteTimeout = 10 * time.Second
+	webSocketMaxFrame     = 4096
+)
+
+type envelope struct {
+	Type string          `json:"type"`
+	Ref  string          `json:"ref,omitempty"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+var (
+	jsonMarshaler   = jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+	jsonUnmarshaler = jsonpb.Unmarshaler{AllowUnknownFields: true}
+	upgrader        = websocket.Upgrader{CheckOrigin: checkOrigin}
+)
+
+// wsConn is a player's WebSocket. Broadcasts and replies go through the same
+// queue, whose goroutine is the only one writing messages.
+type wsConn struct {
+	*subscriber
+	conn *websocket.Conn
+}
+
+func (c *wsConn) Send(m *pb.MessageResponse) error {
+	return c.write(envelopeMessage, "", m)
+}
+
+// write queues the envelope, which is encoded by the queue's goroutine rather
+// than the broadcaster's.
+func (c *wsConn) write(typ, ref string, m proto.Message) error {
+	return c.push(func() error { return c.writeNow(typ, ref, m) })
+}
+
+// writeNow writes the envelope straight away. Only the queue's goroutine may
+// call it until the queue has been closed and waited for.
+func (c *wsConn) writeNow(typ, ref string, m proto.Message) error {
+	data, err := jsonMarshaler.MarshalToString(m)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(envelope{Type: typ, Ref: ref, Data: json.RawMessage(data)})
+	if err != nil {
+		return err
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout))
+	return c.conn.WriteMessage(websocket.TextMessage, b)
+}
+
+// ping pings the client every heartbeat interval until the connection closes,
+// so browsers stay connected without sending heartbeats themselves.
+func (c *wsConn) ping() {
+	for {
+		d := heartbeatInterval()
+		if d <= 0 {
+			return
+		}
+
+		select {
+		case <-c.Context().Done():
+			return
+		case <-time.After(d):
+		}
+		if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(webSocketWriteTimeout)); err != nil {
+			return
+		}
+	}
+}
+
+// wsPlayers sends a WebSocket player the player lists.
+type wsPlayers struct {
+	*wsConn
+}
+
+func (p wsPlayers) Send(m *pb.PlayerListResponse) error {
+	return p.write(envelopePlayers, "", m)
+}
+
+// WebSocketHandler serves WebSocket players on /v1/rooms/{roomKey}/ws and
+// passes every other request to next, or answers 404 if next is nil.
+func (s *Server) WebSocketHandler(next http.Handler) http.Handler {
+	if next == nil {
+		next = http.NotFoundHandler()
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/rooms/") && strings.HasSuffix(r.URL.Path, webSocketSuffix) {
+			s.serveWebSocket(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	token := r.Header.Get(auth.TokenKey)
+	if token == "" {
+		token = q.Get("token")
+	}
+	md := metadata.Pairs(auth.TokenKey, token)
+	for _, v := range r.Header[http.CanonicalHeaderKey(forwardedForKey)] {
+		md.Append(forwardedForKey, v)
+	}
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+	// Bans are checked against the address the player connects from, as they
+	// are for gRPC players.
+	if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+	}
+
+	m := &pb.MessageStreamRequest{
+		Id:          q.Get("id"),
+		RoomKey:     path.Base(path.Dir(r.URL.Path)),
+		Name:        q.Get("name"),
+		Team:        q.Get("team"),
+		ResumeToken: q.Get("resume"),
+	}
+	if err := s.validateCaller(ctx, m, getMessagesMethod); err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already answered the request.
+		return
+	}
+	defer conn.Close()
+
+	c := &wsConn{subscriber: newSubscriber(ctx), conn: conn}
+
+	err = s.join(m, c)
+	if err == nil {
+		s.addPresence(m.RoomKey, m.Id, wsPlayers{c})
+		go s.readWebSocket(c, m)
+		go c.ping()
+		err = s.keepAliveTillClose(m.Id, m.RoomKey)
+		s.removePresence(m.RoomKey, m.Id, wsPlayers{c})
+	}
+
+	// Writes have a deadline, so waiting for the last one is bounded.
+	c.close()
+	if err != nil {
+		c.writeNow(envelopeError, "", status.Convert(err).Proto())
+	}
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(webSocketWriteTimeout))
+}
+
+// readWebSocket handles the player's frames in order until the connection
+// fails, closes or sends nothing for HEARTBEAT_TIMEOUT, then cancels the
+// connection's context.
+func (s *Server) readWebSocket(c *wsConn, m *pb.MessageStreamRequest) {
+	defer c.cancel()
+	c.conn.SetReadLimit(webSocketMaxFrame)
+	extend := func() {
+		var deadline time.Time
+		if d := settings().HeartbeatTimeout; d > 0 {
+			deadline = time.Now().Add(d)
+		}
+		c.conn.SetReadDeadline(deadline)
+	}
+	c.conn.SetPongHandler(func(string) error {
+		extend()
+		return nil
+	})
+
+	for {
+		extend()
+		_, b, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var e envelope
+		if err := json.Unmarshal(b, &e); err != nil {
+			c.write(envelopeError, "", status.New(codes.InvalidArgument, "frames must be JSON envelopes").Proto())
+			continue
+		}
+
+		switch e.Type {
+		case envelopeSend:
+			s.sendFromWebSocket(c, m, e)
+		case envelopeHeartbeat:
+			var h pb.Heartbeat
+			if len(e.Data) > 0 {
+				if err := jsonUnmarshaler.Unmarshal(strings.NewReader(string(e.Data)), &h); err != nil {
+					c.write(envelopeError, e.Ref, status.Newf(codes.InvalidArgument, "invalid heartbeat: %v", err).Proto())
+					continue
+				}
+			}
+			if h.Active {
+				s.markActive(m.RoomKey, m.Id)
+			}
+			c.write(envelopeHeartbeat, e.Ref, &pb.Heartbeat{})
+		default:
+			c.write(envelopeError, e.Ref, status.Newf(codes.InvalidArgument, "unknown type %q", e.Type).Proto())
+		}
+	}
+}
+
+// sendFromWebSocket is SendMessage for a WebSocket player, with the same rate
+// limits as gRPC players.
+func (s *Server) sendFromWebSocket(c *wsConn, m *pb.MessageStreamRequest, e envelope) {
+	var message pb.MessageRequest
+	if err := jsonUnmarshaler.Unmarshal(strings.NewReader(string(e.Data)), &message); err != nil {
+		c.write(envelopeError, e.Ref, status.Newf(codes.InvalidArgument, "invalid send: %v", err).Proto())
+		return
+	}
+	message.Id, message.RoomKey = m.Id, m.RoomKey
+
+	if seconds := s.limitMessage(&message); seconds > 0 {
+		c.write(envelopeError, e.Ref, status.Convert(errRateLimited(seconds)).Proto())
+		return
+	}
+	r, err := s.SendMessage(c.Context(), &message)
+	if err != nil {
+		c.write(envelopeError, e.Ref, status.Convert(err).Proto())
+		return
+	}
+
+	c.write(envelopeSent, e.Ref, r)
+}
+
+// checkOrigin lets browsers connect from the same host or an origin in
+// ALLOWED_ORIGINS. Clients that aren't browsers send no Origin.
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && u.Host == r.Host {
+		return true
+	}
+
+	for _, o := range strings.Split(settings().AllowedOrigins, ",") {
+		if o = strings.TrimSpace(o); o == "*" || o == origin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeHTTPError answers a request rejected before the upgrade with the HTTP
+// status matching err.
+func writeHTTPError(w http.ResponseWriter, err error) {
+	s := status.Convert(err)
+	http.Error(w, s.Message(), runtime.HTTPStatusFromCode(s.Code()))
+}
@@ -0,0 +1,269 @@
+package imageservice
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Image replicas share rounds through the database. A room's rounds are only
+// started by the replica holding its lease, a Postgres advisory lock taken on
+// a connection kept for the purpose, so it is held until that connection ends
+// and another replica takes over. The leader saves each round to image_round
+// and notifies roundChannel, and every replica, the leader included, sends the
+// saved round to its own streams.
+const roundTable = `CREATE TABLE IF NOT EXISTS image_round (
+	room_key   text PRIMARY KEY,
+	round      bigint NOT NULL,
+	photo_id   text NOT NULL,
+	photo_url  text NOT NULL,
+	words      text[],
+	started_at timestamptz NOT NULL DEFAULT now()
+)`
+
+const (
+	roundChannel = "image_round"
+	// leaseClass keeps room leases apart from other advisory locks.
+	leaseClass = 48
+	// leaseInterval is how often leases of rooms without a leader are tried.
+	leaseInterval = 5 * time.Second
+	// followInterval is how often every room's round is reloaded, in case a
+	// notification was missed.
+	followInterval = 30 * time.Second
+)
+
+var errNotLeading = errors.New("not leading the room")
+
+type round struct {
+	number int64
+	image  image
+	words  []string
+}
+
+// leads reports whether this replica starts the room's rounds.
+func (s *Server) leads(roomKey string) bool {
+	s.leaseLock.Lock()
+	defer s.leaseLock.Unlock()
+
+	return s.leading[roomKey]
+}
+
+// holdLeases takes the lease of every room without a leader, every
+// leaseInterval until shutdown.
+func (s *Server) holdLeases() {
+	t := time.NewTicker(leaseInterval)
+	defer t.Stop()
+
+	for {
+		s.takeLeases()
+
+		select {
+		case <-s.shutdown:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (s *Server) takeLeases() {
+	ctx, cancel := context.WithTimeout(context.Background(), leaseInterval)
+	defer cancel()
+
+	s.leaseLock.Lock()
+	defer s.leaseLock.Unlock()
+
+	if s.leaseConn != nil {
+		if err := s.leaseConn.PingContext(ctx); err != nil {
+			log.Printf("Lost room leases: %v", err)
+			s.releaseLeases()
+		}
+	}
+	if s.leaseConn == nil {
+		conn, err := s.DB.Conn(ctx)
+		if err != nil {
+			log.Printf("Failed to connect for room leases: %v", err)
+			return
+		}
+		s.leaseConn = conn
+	}
+
+	for _, roomKey := range rooms {
+		if s.leading[roomKey] {
+			continue
+		}
+
+		var ok bool
+		stmt := "SELECT pg_try_advisory_lock($1, hashtext($2))"
+		if err := s.leaseConn.QueryRowContext(ctx, stmt, leaseClass, roomKey).Scan(&ok); err != nil {
+			log.Printf("Lost room leases: %v", err)
+			s.releaseLeases()
+			return
+		}
+		if ok {
+			log.Printf("Leading Room: %s", roomKey)
+			s.leading[roomKey] = true
+			go s.takeOver(roomKey)
+		}
+	}
+}
+
+// releaseLeases gives up every lease by closing the lease connection. It must
+// be called with s.leaseLock held.
+func (s *Server) releaseLeases() {
+	s.leading = make(map[string]bool)
+	if s.leaseConn == nil {
+		return
+	}
+
+	// Postgres releases the locks once the connection is closed, rather than
+	// returned to the pool to be used with them still held.
+	s.leaseConn.Raw(func(interface{}) error {
+		return driver.ErrBadConn
+	})
+	s.leaseConn.Close()
+	s.leaseConn = nil
+}
+
+// takeOver catches up with the room's last round once this replica leads it,
+// starting one straight away if the room has none.
+func (s *Server) takeOver(roomKey string) {
+	if err := s.followRound(roomKey); err != nil {
+		log.Println(err)
+	}
+
+	s.lock.RLock()
+	started := roomRound[roomKey] > 0
+	s.lock.RUnlock()
+
+	if !started {
+		s.startRound(roomKey)
+	}
+}
+
+// saveRound saves r as the room's next round and notifies every replica,
+// returning its number. It is saved over the lease connection, so it fails
+// once the lease has been lost.
+func (s *Server) saveRound(roomKey string, r round) (int64, error) {
+	s.leaseLock.Lock()
+	conn, ok := s.leaseConn, s.leading[roomKey]
+	s.leaseLock.Unlock()
+
+	if !ok {
+		return 0, errNotLeading
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), leaseInterval)
+	defer cancel()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("save round for %s: %w", roomKey, err)
+	}
+	defer tx.Rollback()
+
+	var n int64
+	stmt := `INSERT INTO image_round (room_key, round, photo_id, photo_url, words)
+			VALUES ($1, 1, $2, $3, $4)
+			ON CONFLICT (room_key) DO UPDATE SET
+				round = image_round.round + 1,
+				photo_id = excluded.photo_id,
+				photo_url = excluded.photo_url,
+				words = excluded.words,
+				started_at = now()
+			RETURNING round`
+	if err := tx.QueryRowContext(ctx, stmt, roomKey, r.image.Id, r.image.Url, pq.Array(r.words)).Scan(&n); err != nil {
+		return 0, fmt.Errorf("save round for %s: %w", roomKey, err)
+	}
+	if _, err := tx.ExecContext(ctx, "SELECT pg_notify($1, $2)", roundChannel, roomKey); err != nil {
+		return 0, fmt.Errorf("notify round for %s: %w", roomKey, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("save round for %s: %w", roomKey, err)
+	}
+
+	return n, nil
+}
+
+// setRound makes r the room's current round if it is newer, returning whether
+// it was.
+func (s *Server) setRound(roomKey string, r round) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.roomImageWordStreams[roomKey]; !ok || r.number <= roomRound[roomKey] {
+		return false
+	}
+	roomRound[roomKey] = r.number
+	roomImage[roomKey] = r.image
+	roomWord[roomKey] = r.words
+
+	return true
+}
+
+// followRounds sends the rounds saved by each room's leader to the streams
+// here, until shutdown.
+func (s *Server) followRounds() {
+	// Listen waits for the database, and fails once the listener is closed.
+	if err := s.listener.Listen(roundChannel); err != nil {
+		log.Printf("Failed to listen for rounds: %v", err)
+		return
+	}
+	s.followAll()
+
+	t := time.NewTicker(followInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-s.shutdown:
+			return
+		case n, ok := <-s.listener.Notify:
+			if !ok {
+				return
+			}
+			// A nil notification means the listener reconnected, and may
+			// have missed some.
+			if n == nil {
+				s.followAll()
+			} else if err := s.followRound(n.Extra); err != nil {
+				log.Println(err)
+			}
+		case <-t.C:
+			s.followAll()
+		}
+	}
+}
+
+func (s *Server) followAll() {
+	for _, roomKey := range rooms {
+		if err := s.followRound(roomKey); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// followRound loads the room's last round, sending it to the room's streams if
+// it is new.
+func (s *Server) followRound(roomKey string) error {
+	var r round
+	stmt := "SELECT round, photo_id, photo_url, words FROM image_round WHERE room_key = $1"
+	err := s.DB.QueryRow(stmt, roomKey).Scan(&r.number, &r.image.Id, &r.image.Url, pq.Array(&r.words))
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get round for %s: %w", roomKey, err)
+	}
+
+	if s.setRound(roomKey, r) {
+		s.sendImageAndWords(roomKey)
+	}
+
+	return nil
+}
@@ -1,49 +1,66 @@
-package main
+package imageservice
 
 import (
 	"context"
 	"database/sql"
-	"flag"
 	"fmt"
 	"log"
-	"net"
+	"sync"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
-	c "github.com/richardjaytea/infipic/config"
+	"github.com/richardjaytea/infipic/auth"
+	"github.com/richardjaytea/infipic/config"
+	"github.com/richardjaytea/infipic/discovery"
+	"github.com/richardjaytea/infipic/healthcheck"
+	"github.com/richardjaytea/infipic/mtls"
 	"github.com/richardjaytea/infipic/pb"
+	"github.com/richardjaytea/infipic/retry"
 	"github.com/robfig/cron/v3"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/examples/data"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 var (
-	tls                = flag.Bool("tls", false, "Connection uses TLS if true, else plain TCP")
-	certFile           = flag.String("cert_file", "", "The TLS cert file")
-	keyFile            = flag.String("key_file", "", "The TLS key file")
-	jsonDBFile         = flag.String("json_db_file", "", "A json file containing a list of features")
-	port               = flag.Int("port", 10001, "The server port")
-	emp                = empty.Empty{}
-	caFile             = flag.String("ca_file", "", "The file containing the CA root cert file")
-	serverAddrRoom     = flag.String("server_addr_room", "localhost:10003", "The server address for the room service server")
-	serverHostOverride = flag.String("server_host_override", "x.test.youtube.com", "The server name used to verify the hostname returned by the TLS handshake")
+	conf = config.DefaultImage()
+	// confLock guards the fields of conf that are reloaded while running.
+	confLock sync.RWMutex
 )
 
 var (
 	rooms     []string
 	roomImage map[string]image
 	roomWord  map[string][]string
+	roomRound map[string]int64
 )
 
-type imageWordStreams map[string]*pb.Image_GetImageAndWordsServer
+type imageWordStreams map[string]*subscriber
 
-type imageServer struct {
+type Server struct {
 	pb.UnimplementedImageServer
+	// lock guards roomImageWordStreams along with roomImage, roomWord and
+	// roomRound.
+	lock                 sync.RWMutex
 	roomImageWordStreams map[string]imageWordStreams
 	roomClient           pb.RoomClient
+	roomConn             *grpc.ClientConn
 	DB                   *sql.DB
+	health               *healthcheck.Service
+	cron                 *cron.Cron
+	cronEntry            cron.EntryID
+	// certs is the certificate Room is dialed with over TLS.
+	certs *mtls.Source
+	// leaseLock guards leaseConn, which holds the leases of the rooms in
+	// leading.
+	leaseLock sync.Mutex
+	leaseConn *sql.Conn
+	leading   map[string]bool
+	listener  *pq.Listener
+	// shutdown is closed when the server is stopping, ending every stream.
+	shutdown chan struct{}
 }
 
 type image struct {
@@ -51,103 +68,170 @@ type image struct {
 	Url string `json:"photo_image_url"`
 }
 
-func (s *imageServer) GetImageAndWords(r *pb.Client, stream pb.Image_GetImageAndWordsServer) error {
-	s.roomImageWordStreams[r.RoomKey][r.Id] = &stream
+func (s *Server) GetImageAndWords(r *pb.Client, stream pb.Image_GetImageAndWordsServer) error {
+	if err := s.validateClient(stream.Context(), r); err != nil {
+		return err
+	}
+
+	sub := newSubscriber(stream)
+	defer sub.close()
+
+	s.lock.Lock()
+	s.roomImageWordStreams[r.RoomKey][r.Id] = sub
+	s.lock.Unlock()
+
 	s.sendImageToUser(r.RoomKey, r.Id)
 	log.Printf("ImageWord Stream Created: %s %s", r.RoomKey, r.Id)
+	var err error
 	select {
-	case <-stream.Context().Done():
-		// TODO: Lock map for deletion?
+	case <-sub.ctx.Done():
+	case <-s.shutdown:
+		err = status.Error(codes.Unavailable, "server restarting")
+	}
+
+	s.lock.Lock()
+	if s.roomImageWordStreams[r.RoomKey][r.Id] == sub {
 		delete(s.roomImageWordStreams[r.RoomKey], r.Id)
-		log.Printf("ImageWord Connection Disconnected: %s %s", r.RoomKey, r.Id)
-		return nil
 	}
+	s.lock.Unlock()
+	log.Printf("ImageWord Connection Disconnected: %s %s", r.RoomKey, r.Id)
+	return err
 }
 
-func (s *imageServer) sendImageAndWords(roomKey string) {
-	for _, stream := range s.roomImageWordStreams[roomKey] {
-		if stream != nil && *stream != nil {
-			if err := (*stream).Send(&pb.ImageWordResponse{
-				Content: roomImage[roomKey].Url,
-				Words:   roomWord[roomKey],
-			}); err != nil {
-				log.Println(err)
-			}
-		}
+// validateClient makes sure the room exists and the token was issued for the
+// client and room.
+func (s *Server) validateClient(ctx context.Context, r *pb.Client) error {
+	if r.Id == "" || r.RoomKey == "" {
+		return status.Error(codes.InvalidArgument, "id and roomKey are required")
+	}
+	s.lock.RLock()
+	_, ok := s.roomImageWordStreams[r.RoomKey]
+	s.lock.RUnlock()
+
+	if !ok {
+		return status.Errorf(codes.NotFound, "room %s does not exist", r.RoomKey)
 	}
+	if !auth.Verify(settings().AuthSecret, r.Id, r.RoomKey, auth.FromContext(ctx)) {
+		return status.Error(codes.PermissionDenied, "invalid client token")
+	}
+
+	return nil
 }
 
-func (s *imageServer) sendImageToUser(roomKey string, id string) {
-	if stream, ok := s.roomImageWordStreams[roomKey][id]; ok {
-		if err := (*stream).Send(&pb.ImageWordResponse{
-			Content: roomImage[roomKey].Url,
-			Words:   roomWord[roomKey],
-		}); err != nil {
-			log.Println(err)
-		}
+func (s *Server) sendImageAndWords(roomKey string) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	m := &pb.ImageWordResponse{
+		Content: roomImage[roomKey].Url,
+		Words:   roomWord[roomKey],
+	}
+	for _, sub := range s.roomImageWordStreams[roomKey] {
+		sub.send(m)
 	}
 }
 
-func newServer() *imageServer {
-	connectionString := fmt.Sprintf("host=%s port=%s user=%s "+
-		"password=%s dbname=%s sslmode=disable",
-		c.VGetEnv("APP_DB_HOST"),
-		c.VGetEnv("APP_DB_PORT"),
-		c.VGetEnv("APP_DB_USERNAME"),
-		c.VGetEnv("APP_DB_PASSWORD"),
-		c.VGetEnv("APP_DB_NAME"))
+func (s *Server) sendImageToUser(roomKey string, id string) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	db, err := sql.Open("postgres", connectionString)
-	if err != nil {
-		panic(err)
+	if sub, ok := s.roomImageWordStreams[roomKey][id]; ok {
+		sub.send(&pb.ImageWordResponse{
+			Content: roomImage[roomKey].Url,
+			Words:   roomWord[roomKey],
+		})
 	}
+}
 
-	err = db.Ping()
+// New returns the Image service. Rooms are loaded and rounds scheduled by
+// Start, once the server is serving.
+func New(hs *healthcheck.Server) *Server {
+	db, err := sql.Open("postgres", settings().ConnectionString())
 	if err != nil {
 		panic(err)
 	}
 
-	log.Println("Connected to database!")
+	// Until the database is up rooms keep their current image.
+	go retry.Do(context.Background(), "connect to database", func() error {
+		if err := db.Ping(); err != nil {
+			return err
+		}
+		if _, err := db.Exec(roundTable); err != nil {
+			return err
+		}
+		log.Println("Connected to database!")
+		return nil
+	})
 
-	s := &imageServer{
+	s := &Server{
 		roomImageWordStreams: make(map[string]imageWordStreams),
 		DB:                   db,
+		health:               hs.Service("pb.Image", "db", "room"),
+		cron:                 cron.New(),
+		leading:              make(map[string]bool),
+		listener: pq.NewListener(settings().ConnectionString(), time.Second, time.Minute, func(_ pq.ListenerEventType, err error) {
+			if err != nil {
+				log.Printf("Round listener: %v", err)
+			}
+		}),
+		shutdown: make(chan struct{}),
 	}
+	go s.health.Poll(context.Background(), "db", db.PingContext)
 	roomImage = make(map[string]image)
 	roomWord = make(map[string][]string)
+	roomRound = make(map[string]int64)
 
-	s.connectServices()
+	return s
+}
 
+// Start connects to Room, loads the rooms, and starts the rounds of the rooms
+// this replica leads and follows the rest. opts are added to the options used
+// to dial Room.
+func (s *Server) Start(opts ...grpc.DialOption) {
+	s.connectServices(opts)
+	select {
+	case <-s.shutdown:
+		// Stopped while waiting for Room.
+		return
+	default:
+	}
+
+	s.lock.Lock()
 	for _, v := range rooms {
 		s.roomImageWordStreams[v] = imageWordStreams{}
 		roomImage[v] = image{}
 		roomWord[v] = []string{}
 	}
+	s.lock.Unlock()
 
-	return s
+	go s.holdLeases()
+	go s.followRounds()
+	s.startCron()
 }
 
-func (s *imageServer) getRooms() {
+func (s *Server) getRooms() error {
 	r, err := s.roomClient.GetRooms(context.Background(), &empty.Empty{})
 	if err != nil {
-		log.Fatal("error in trying to get rooms from room service")
-		return
+		return err
 	}
 
+	rooms = nil
 	for _, v := range r.Rooms {
 		rooms = append(rooms, v.GetKey())
 	}
+
+	return nil
 }
 
-func (s *imageServer) getRandomImage(roomKey string) {
+func (s *Server) getRandomImage() (image, error) {
 	var i image
 	stmt := "SELECT photo_id, photo_image_url FROM unsplash_photos ORDER BY random() LIMIT 1"
-	s.DB.QueryRow(stmt).Scan(&i.Id, &i.Url)
+	err := s.DB.QueryRow(stmt).Scan(&i.Id, &i.Url)
 
-	roomImage[roomKey] = i
+	return i, err
 }
 
-func (s *imageServer) getImageKeywords(roomKey string) {
+func (s *Server) getImageKeywords(id string) ([]string, error) {
 	stmt := `select
 				keyword
 			from
@@ -155,21 +239,20 @@ func (s *imageServer) getImageKeywords(roomKey string) {
 			where
 				photo_id = $1
 				and ((ai_service_1_confidence is not null
-				and ai_service_1_confidence > 40.0)
+				and ai_service_1_confidence > $2)
 				or (ai_service_2_confidence is not null
-				and ai_service_2_confidence > 40.0))
+				and ai_service_2_confidence > $2))
 				and suggested_by_user = false
 				and keyword not like '% %'
 				and keyword not like '%-%'
 			order by
 				ai_service_1_confidence desc
-			limit 6`
+			limit $3`
 
-	id := roomImage[roomKey].Id
-	r, err := s.DB.Query(stmt, id)
-	if err != nil && err != sql.ErrNoRows {
-		log.Fatalf("Error trying to get keywords for ID: %s", id)
-		return
+	cfg := settings()
+	r, err := s.DB.Query(stmt, id, cfg.KeywordMinConfidence, cfg.KeywordsPerImage)
+	if err != nil {
+		return nil, fmt.Errorf("get keywords for ID %s: %w", id, err)
 	}
 
 	var k []string
@@ -177,84 +260,168 @@ func (s *imageServer) getImageKeywords(roomKey string) {
 
 	defer r.Close()
 	for r.Next() {
-		r.Scan(&v)
+		if err := r.Scan(&v); err != nil {
+			return nil, fmt.Errorf("read keyword for ID %s: %w", id, err)
+		}
 		k = append(k, v)
 	}
 
-	roomWord[roomKey] = k
-	log.Println(roomWord[roomKey])
+	return k, r.Err()
+}
+
+// refreshRoom starts a new round in the room with a new image and its keywords.
+func (s *Server) refreshRoom(roomKey string) error {
+	i, err := s.getRandomImage()
+	if err != nil {
+		return err
+	}
+
+	k, err := s.getImageKeywords(i.Id)
+	if err != nil {
+		return err
+	}
+
+	r := round{image: i, words: k}
+	if r.number, err = s.saveRound(roomKey, r); err != nil {
+		return err
+	}
+	if s.setRound(roomKey, r) {
+		s.sendImageAndWords(roomKey)
+	}
+
+	log.Println(k)
+	return nil
 }
 
-func (s *imageServer) refreshImageAndSendFunc() func() {
+// refreshImageAndSendFunc refreshes every room this replica leads.
+func (s *Server) refreshImageAndSendFunc() func() {
 	return func() {
 		for _, v := range rooms {
-			s.getRandomImage(v)
-			s.getImageKeywords(v)
-			go s.sendImageAndWords(v)
+			if s.leads(v) {
+				go s.startRound(v)
+			}
 		}
 	}
 }
 
-func (s *imageServer) startCron() {
-	c := cron.New()
-	c.AddFunc("@every 30s", s.refreshImageAndSendFunc())
-	c.Start()
-}
+// startRound refreshes the room, retrying failures until the next refresh is
+// close or the room's lease is lost. Rooms that still fail keep their current
+// image.
+func (s *Server) startRound(roomKey string) {
+	ctx, cancel := context.WithTimeout(context.Background(), settings().RoundInterval/2)
+	defer cancel()
 
-func (s *imageServer) connectServices() {
-	flag.Parse()
-	var opts []grpc.DialOption
-	if *tls {
-		if *caFile == "" {
-			*caFile = data.Path("x509/ca_cert.pem")
+	err := retry.Do(ctx, "refresh room "+roomKey, func() error {
+		if !s.leads(roomKey) {
+			return nil
 		}
-		creds, err := credentials.NewClientTLSFromFile(*caFile, *serverHostOverride)
-		if err != nil {
-			log.Fatalf("Failed to create TLS credentials %v", err)
-		}
-		opts = append(opts, grpc.WithTransportCredentials(creds))
-	} else {
-		opts = append(opts, grpc.WithInsecure())
+		return s.refreshRoom(roomKey)
+	})
+	if err != nil {
+		log.Printf("Keeping current image for %s: %v", roomKey, err)
 	}
+}
+
+func (s *Server) startCron() {
+	s.ScheduleRounds()
+	s.cron.Start()
+}
 
-	opts = append(opts, grpc.WithBlock())
+// ScheduleRounds (re)schedules the refresh every ROUND_INTERVAL.
+func (s *Server) ScheduleRounds() {
+	s.cron.Remove(s.cronEntry)
 
-	conn, err := grpc.Dial(*serverAddrRoom, opts...)
+	entry, err := s.cron.AddFunc(fmt.Sprintf("@every %s", settings().RoundInterval), s.refreshImageAndSendFunc())
 	if err != nil {
-		log.Fatalf("fail to dial: %v", err)
+		log.Printf("Failed to schedule rounds: %v", err)
+		return
 	}
+	s.cronEntry = entry
+}
 
-	s.roomClient = pb.NewRoomClient(conn)
-	s.getRooms()
+// Drain stops new rounds and ends all streams.
+func (s *Server) Drain() {
+	s.cron.Stop()
+	close(s.shutdown)
 }
 
-func main() {
-	flag.Parse()
-	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", *port))
-	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+// Close gives up the room leases, so another replica can take over, and closes
+// the connection to Room and the database.
+func (s *Server) Close() error {
+	if s.certs != nil {
+		s.certs.Close()
 	}
-	var opts []grpc.ServerOption
-	if *tls {
-		if *certFile == "" {
-			*certFile = data.Path("x509/server_cert.pem")
-		}
-		if *keyFile == "" {
-			*keyFile = data.Path("x509/server_key.pem")
+	s.leaseLock.Lock()
+	s.releaseLeases()
+	s.leaseLock.Unlock()
+	s.listener.Close()
+
+	if s.roomConn != nil {
+		if err := s.roomConn.Close(); err != nil {
+			return err
 		}
-		creds, err := credentials.NewServerTLSFromFile(*certFile, *keyFile)
+	}
+
+	return s.DB.Close()
+}
+
+func (s *Server) connectServices(opts []grpc.DialOption) {
+	cfg := settings()
+	creds := func(string) grpc.DialOption {
+		return grpc.WithInsecure()
+	}
+	if cfg.TLS {
+		certs, err := mtls.Load(cfg.CertFile, cfg.KeyFile, cfg.CAFile, cfg.TrustDomain)
 		if err != nil {
-			log.Fatalf("Failed to generate credentials %v", err)
+			log.Fatalf("Failed to load TLS certificates: %v", err)
+		}
+		s.certs = certs
+		// Each service dialed must prove it is the one expected.
+		creds = func(service string) grpc.DialOption {
+			return grpc.WithTransportCredentials(certs.ClientCredentials(service))
 		}
-		opts = []grpc.ServerOption{grpc.Creds(creds)}
 	}
-	grpcServer := grpc.NewServer(opts...)
-	s := newServer()
-	pb.RegisterImageServer(grpcServer, s)
 
-	s.startCron()
+	conn, err := discovery.Dial(cfg.ServerAddrRoom, "pb.Room", append(opts, creds("room"))...)
+	if err != nil {
+		log.Fatalf("fail to dial: %v", err)
+	}
 
-	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("failed to serve Chat: %v", err)
+	s.roomConn = conn
+	s.roomClient = pb.NewRoomClient(conn)
+	// Rounds are for Room's rooms, so wait for it however long it takes,
+	// unready until then.
+	for {
+		err := healthcheck.WaitReady(conn, "pb.Room", cfg.ReadyTimeout)
+		if err == nil {
+			break
+		}
+		log.Printf("Room not ready after %s, still waiting: %v", cfg.ReadyTimeout, err)
+		select {
+		case <-s.shutdown:
+			return
+		default:
+		}
 	}
+	retry.Do(context.Background(), "get rooms", s.getRooms)
+	go s.health.Poll(context.Background(), "room", func(ctx context.Context) error {
+		return healthcheck.Check(ctx, conn, "pb.Room")
+	})
+}
+
+// Configure replaces the config the service runs with. Call ScheduleRounds
+// afterwards for a new ROUND_INTERVAL to take effect.
+func Configure(c config.Image) {
+	confLock.Lock()
+	defer confLock.Unlock()
+
+	conf = c
+}
+
+// settings returns a copy of the config that is safe to use during reloads.
+func settings() config.Image {
+	confLock.RLock()
+	defer confLock.RUnlock()
+
+	return conf
 }
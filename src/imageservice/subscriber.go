@@ -0,0 +1,46 @@
+package imageservice
+
+import (
+	"context"
+
+	"github.com/richardjaytea/infipic/fanout"
+	"github.com/richardjaytea/infipic/pb"
+)
+
+// sendQueues reports the depth of every client's queue in /debug/vars.
+var sendQueues = fanout.NewGroup("image_send_queues")
+
+// subscriber queues the images sent to a GetImageAndWords stream, so a slow
+// client does not hold up the rest of the room. Its context ends with the
+// stream, or when SLOW_CONSUMER_POLICY disconnects it.
+type subscriber struct {
+	q      *fanout.Queue
+	ctx    context.Context
+	cancel context.CancelFunc
+	stream pb.Image_GetImageAndWordsServer
+}
+
+func newSubscriber(stream pb.Image_GetImageAndWordsServer) *subscriber {
+	cfg := settings()
+	ctx, cancel := context.WithCancel(stream.Context())
+
+	return &subscriber{
+		q:      sendQueues.NewQueue(cfg.SendQueueSize, cfg.SlowConsumerPolicy, cancel),
+		ctx:    ctx,
+		cancel: cancel,
+		stream: stream,
+	}
+}
+
+func (sub *subscriber) send(r *pb.ImageWordResponse) {
+	sub.q.Push(func() error { return sub.stream.Send(r) })
+}
+
+// close stops queueing sends and waits for the ones already queued to be made,
+// for up to fanout.FlushTimeout, so the last of them, such as a restart
+// notice, goes out before the stream ends.
+func (sub *subscriber) close() {
+	sub.q.Close()
+	sub.cancel()
+	sub.q.Wait()
+}
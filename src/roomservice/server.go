@@ -1,33 +1,49 @@
-package main
+package roomservice
 
 import (
 	"context"
 	"database/sql"
-	"flag"
-	"fmt"
 	"github.com/golang/protobuf/ptypes/empty"
-	c "github.com/richardjaytea/infipic/config"
+	"github.com/richardjaytea/infipic/config"
+	"github.com/richardjaytea/infipic/healthcheck"
+	"github.com/richardjaytea/infipic/mtls"
 	"github.com/richardjaytea/infipic/pb"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/examples/data"
+	"github.com/richardjaytea/infipic/retry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"log"
-	"net"
+	"sync"
 
 	_ "github.com/lib/pq"
 )
 
 var (
-	tls        = flag.Bool("tls", false, "Connection uses TLS if true, else plain TCP")
-	certFile   = flag.String("cert_file", "", "The TLS cert file")
-	keyFile    = flag.String("key_file", "", "The TLS key file")
-	jsonDBFile = flag.String("json_db_file", "", "A json file containing a list of features")
-	port       = flag.Int("port", 10003, "The server port")
+	conf     = config.DefaultRoom()
+	confLock sync.RWMutex
 )
 
-type roomServer struct {
+const banTable = `CREATE TABLE IF NOT EXISTS room_ban (
+	room_key   text NOT NULL,
+	player_id  text NOT NULL,
+	name       text NOT NULL,
+	reason     text NOT NULL DEFAULT '',
+	address    text NOT NULL DEFAULT '',
+	created_at timestamptz NOT NULL DEFAULT now()
+)`
+
+// banAddress adds the address column to tables made before bans had it.
+const banAddress = `ALTER TABLE room_ban ADD COLUMN IF NOT EXISTS address text NOT NULL DEFAULT ''`
+
+// banService is the only service that may add bans, for the players it bans.
+const banService = "chat"
+
+// Server is the Room service. It trusts its callers with players' bans, so
+// must never be exposed to players: only the other services and the gateway,
+// which only serves GetRooms, should be able to reach it.
+type Server struct {
 	pb.UnimplementedRoomServer
-	DB *sql.DB
+	DB     *sql.DB
+	health *healthcheck.Service
 }
 
 type room struct {
@@ -35,7 +51,7 @@ type room struct {
 	Key  string `json:"key"`
 }
 
-func (s *roomServer) GetRooms(ctx context.Context, e *empty.Empty) (*pb.RoomResponse, error) {
+func (s *Server) GetRooms(ctx context.Context, e *empty.Empty) (*pb.RoomResponse, error) {
 	r, err := s.GetAllRooms()
 	if err != nil {
 		return nil, err
@@ -46,80 +62,126 @@ func (s *roomServer) GetRooms(ctx context.Context, e *empty.Empty) (*pb.RoomResp
 	}, nil
 }
 
-func (s *roomServer) GetAllRooms() ([]*pb.RoomDetail, error) {
+func (s *Server) GetAllRooms() ([]*pb.RoomDetail, error) {
 	var r *pb.RoomDetail
 	var a []*pb.RoomDetail
 
 	stmt := "SELECT name, key FROM room ORDER BY name"
 	result, err := s.DB.Query(stmt)
-	if err != nil && err != sql.ErrNoRows {
-		log.Fatalln("Error trying to get rooms")
-		return nil, err
+	if err != nil {
+		log.Printf("Error trying to get rooms: %v", err)
+		return nil, status.Error(codes.Unavailable, "could not get rooms")
 	}
 
 	defer result.Close()
 	for result.Next() {
 		r = new(pb.RoomDetail)
-		result.Scan(&r.Name, &r.Key)
+		if err := result.Scan(&r.Name, &r.Key); err != nil {
+			log.Printf("Error trying to read room: %v", err)
+			return nil, status.Error(codes.Internal, "could not read rooms")
+		}
 		a = append(a, r)
 	}
+	if err := result.Err(); err != nil {
+		log.Printf("Error trying to get rooms: %v", err)
+		return nil, status.Error(codes.Unavailable, "could not get rooms")
+	}
 
 	return a, nil
 }
 
-func newServer() *roomServer {
-	connectionString := fmt.Sprintf("host=%s port=%s user=%s "+
-		"password=%s dbname=%s sslmode=disable",
-		c.VGetEnv("APP_DB_HOST"),
-		c.VGetEnv("APP_DB_PORT"),
-		c.VGetEnv("APP_DB_USERNAME"),
-		c.VGetEnv("APP_DB_PASSWORD"),
-		c.VGetEnv("APP_DB_NAME"))
+// AddBan saves a ban. Over TLS only Chat may add one, the others only reading
+// them.
+func (s *Server) AddBan(ctx context.Context, b *pb.Ban) (*empty.Empty, error) {
+	if c := settings(); c.TLS {
+		if id := mtls.PeerID(ctx); id != mtls.ID(c.TrustDomain, banService) {
+			log.Printf("Refused ban for %s in %s from %q", b.PlayerId, b.RoomKey, id)
+			return nil, status.Error(codes.PermissionDenied, "only chat may add bans")
+		}
+	}
 
-	db, err := sql.Open("postgres", connectionString)
-	if err != nil {
-		panic(err)
+	stmt := "INSERT INTO room_ban (room_key, player_id, name, reason, address) VALUES ($1, $2, $3, $4, $5)"
+	if _, err := s.DB.Exec(stmt, b.RoomKey, b.PlayerId, b.Name, b.Reason, b.Address); err != nil {
+		log.Printf("Error trying to add ban for %s in %s: %v", b.PlayerId, b.RoomKey, err)
+		return nil, status.Error(codes.Internal, "could not save ban")
 	}
 
-	err = db.Ping()
+	return &empty.Empty{}, nil
+}
+
+func (s *Server) GetBans(ctx context.Context, r *pb.RoomRequest) (*pb.BanResponse, error) {
+	var b *pb.Ban
+	var a []*pb.Ban
+
+	stmt := "SELECT room_key, player_id, name, reason, address FROM room_ban WHERE room_key = $1 ORDER BY created_at"
+	result, err := s.DB.Query(stmt, r.RoomKey)
 	if err != nil {
-		panic(err)
+		log.Printf("Error trying to get bans for %s: %v", r.RoomKey, err)
+		return nil, status.Error(codes.Internal, "could not get bans")
 	}
 
-	log.Println("Connected to database!")
-
-	s := &roomServer{
-		DB: db,
+	defer result.Close()
+	for result.Next() {
+		b = new(pb.Ban)
+		if err := result.Scan(&b.RoomKey, &b.PlayerId, &b.Name, &b.Reason, &b.Address); err != nil {
+			log.Printf("Error trying to read ban for %s: %v", r.RoomKey, err)
+			return nil, status.Error(codes.Internal, "could not read bans")
+		}
+		a = append(a, b)
+	}
+	if err := result.Err(); err != nil {
+		log.Printf("Error trying to get bans for %s: %v", r.RoomKey, err)
+		return nil, status.Error(codes.Unavailable, "could not get bans")
 	}
 
-	return s
+	return &pb.BanResponse{
+		Bans: a,
+	}, nil
 }
 
-func main() {
-	flag.Parse()
-	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", *port))
+// New returns the Room service, which is healthy while its database is up.
+func New(hs *healthcheck.Server) *Server {
+	db, err := sql.Open("postgres", settings().ConnectionString())
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		panic(err)
 	}
-	var opts []grpc.ServerOption
-	if *tls {
-		if *certFile == "" {
-			*certFile = data.Path("x509/server_cert.pem")
-		}
-		if *keyFile == "" {
-			*keyFile = data.Path("x509/server_key.pem")
+
+	s := &Server{
+		DB:     db,
+		health: hs.Service("pb.Room", "db"),
+	}
+	go s.health.Poll(context.Background(), "db", db.PingContext)
+
+	// The database may come up after us, so keep trying in the background and
+	// answer with Unavailable until it does.
+	go retry.Do(context.Background(), "connect to database", func() error {
+		if err := db.Ping(); err != nil {
+			return err
 		}
-		creds, err := credentials.NewServerTLSFromFile(*certFile, *keyFile)
-		if err != nil {
-			log.Fatalf("Failed to generate credentials %v", err)
+		for _, stmt := range []string{banTable, banAddress} {
+			if _, err := db.Exec(stmt); err != nil {
+				return err
+			}
 		}
-		opts = []grpc.ServerOption{grpc.Creds(creds)}
-	}
-	grpcServer := grpc.NewServer(opts...)
-	s := newServer()
-	pb.RegisterRoomServer(grpcServer, s)
+		log.Println("Connected to database!")
+		return nil
+	})
 
-	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("failed to serve Chat: %v", err)
-	}
+	return s
+}
+
+// Configure replaces the config the service runs with.
+func Configure(c config.Room) {
+	confLock.Lock()
+	defer confLock.Unlock()
+
+	conf = c
+}
+
+// settings returns a copy of the config that is safe to use during reloads.
+func settings() config.Room {
+	confLock.RLock()
+	defer confLock.RUnlock()
+
+	return conf
 }